@@ -0,0 +1,138 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      Incremental validation of the arrays received one item at a time.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+// ArrayValidator validates an array one item at a time, as the items of a
+// stream are received, without keeping them. The array is validated against
+// the root of the schema : items, additionalItems and uniqueItems are checked
+// as the items are added, minItems and maxItems when the array is closed.
+// The other keywords of the root, type included, are not checked.
+// An ArrayValidator is not safe for concurrent use.
+type ArrayValidator struct {
+	schema  *subSchema
+	options ValidateOptions
+	nbItems int
+	// index of the first item of each canonical JSON string, for uniqueItems
+	stringifiedItems map[string]int
+	duplicated       bool
+}
+
+// NewArrayValidator returns an ArrayValidator of the arrays the schema
+// describes, validating with the options.
+func (v *Schema) NewArrayValidator(options ValidateOptions) *ArrayValidator {
+
+	schema := v.rootSchema
+	for visited := make(map[*subSchema]bool); schema.refSchema != nil && !visited[schema]; schema = schema.refSchema {
+		visited[schema] = true
+	}
+
+	a := &ArrayValidator{schema: schema, options: options}
+	if schema.uniqueItems != nil && *schema.uniqueItems {
+		a.stringifiedItems = make(map[string]int)
+	}
+
+	return a
+}
+
+// Add validates the next item of the array. The errors of the result are about
+// the item, at #/i for the item of index i, or about the array, at #, for
+// additionalItems and uniqueItems. Only the first duplicate is reported.
+func (a *ArrayValidator) Add(l JSONLoader) (*Result, error) {
+
+	item, err := l.loadJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	result := a.newResult()
+	context := result.newContext(STRING_CONTEXT_ROOT, nil)
+	i := a.nbItems
+	a.nbItems++
+
+	a.schema.validateItem(item, i, nil, result, context)
+
+	// uniqueItems:
+	if a.stringifiedItems != nil && !a.duplicated {
+		vString, err := marshalToJsonString(item)
+		if err != nil {
+			return nil, err
+		}
+		if first, ok := a.stringifiedItems[*vString]; ok {
+			a.duplicated = true
+			result.addError(
+				context,
+				KEY_UNIQUE_ITEMS,
+				nil,
+				nil,
+				map[string]interface{}{STRING_DUPLICATES: []int{first, i}},
+			)
+		} else {
+			a.stringifiedItems[*vString] = i
+		}
+	}
+
+	return a.finish(result), nil
+}
+
+// Close ends the array, checking minItems and maxItems. The value of their
+// errors is the number of items, the array not being kept.
+func (a *ArrayValidator) Close() *Result {
+
+	result := a.newResult()
+	context := result.newContext(STRING_CONTEXT_ROOT, nil)
+
+	// minItems & maxItems
+	if a.schema.minItems != nil && a.nbItems < *a.schema.minItems {
+		result.AddError(
+			context,
+			KEY_MIN_ITEMS,
+			a.schema.minItems,
+			a.nbItems,
+		)
+	}
+	if a.schema.maxItems != nil && a.nbItems > *a.schema.maxItems {
+		result.AddError(
+			context,
+			KEY_MAX_ITEMS,
+			a.schema.maxItems,
+			a.nbItems,
+		)
+	}
+
+	return a.finish(result)
+}
+
+func (a *ArrayValidator) newResult() *Result {
+	return &Result{options: &a.options}
+}
+
+// Removes the errors reported several times, as validateRoot does
+func (a *ArrayValidator) finish(result *Result) *Result {
+	if !a.options.IsValid {
+		result.errors = ResultErrors(result.errors).Dedup()
+	}
+	return result
+}
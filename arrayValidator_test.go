@@ -0,0 +1,97 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for the incremental validation of arrays.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestArrayValidator(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"$ref": "#/definitions/list",
+		"definitions": {"list": {"items": {"type": "integer", "minimum": 0}, "uniqueItems": true, "minItems": 2, "maxItems": 3}}
+	}`))
+	assert.Nil(t, err)
+
+	reasons := func(result *Result) []string {
+		var reasons []string
+		for _, rerr := range result.Errors() {
+			reasons = append(reasons, rerr.Context.String()+" "+rerr.Reason)
+		}
+		return reasons
+	}
+
+	a := schema.NewArrayValidator(ValidateOptions{})
+	for i, expected := range [][]string{nil, {"#/1 minimum"}, {"#/2 type"}, {"# uniqueItems"}, nil} {
+		document := []string{`1`, `-1`, `"a"`, `1`, `1`}[i]
+		result, err := a.Add(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, expected, reasons(result), document)
+	}
+	assert.Equal(t, []string{"# maxItems"}, reasons(a.Close()))
+
+	a = schema.NewArrayValidator(ValidateOptions{})
+	result, err := a.Add(NewGoLoader(1))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+	result = a.Close()
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, KEY_MIN_ITEMS, result.Errors()[0].Reason)
+		assert.Equal(t, 1, result.Errors()[0].Value)
+	}
+
+	_, err = a.Add(NewStringLoader(`{`))
+	assert.NotNil(t, err)
+}
+
+func TestArrayValidatorTuple(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"items": [{"type": "string"}, {"type": "integer"}], "additionalItems": false}`))
+	assert.Nil(t, err)
+
+	// the same errors as the validation of the whole array
+	documents := []string{`"a"`, `"b"`, `null`, `true`}
+	whole, err := schema.Validate(NewStringLoader(`["a", "b", null, true]`))
+	assert.Nil(t, err)
+
+	a := schema.NewArrayValidator(ValidateOptions{})
+	var streamed ResultErrors
+	for _, document := range documents {
+		result, err := a.Add(NewStringLoader(document))
+		assert.Nil(t, err)
+		streamed = append(streamed, result.Errors()...)
+	}
+	streamed = append(streamed, a.Close().Errors()...)
+
+	assert.Len(t, streamed, 2)
+	for i := range streamed {
+		assert.Equal(t, whole.Errors()[i].Context.String(), streamed[i].Context.String())
+		assert.Equal(t, whole.Errors()[i].Reason, streamed[i].Reason)
+	}
+}
@@ -0,0 +1,141 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      Deep copy of a schema.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+// Clone returns a deep copy of the schema, so the copy can be modified with
+// the setters of its subSchemas without affecting the original, which may be
+// in use by other goroutines.
+// Compiled regexes, JSON references and custom keywords are immutable and
+// shared by both.
+func (d *Schema) Clone() *Schema {
+
+	c := &cloner{clones: make(map[*subSchema]*subSchema)}
+
+	clone := *d
+	clone.referencePool = newSchemaReferencePool()
+	clone.rootSchema = c.clone(d.rootSchema)
+	// the setters may give the copy an anyOf or a oneOf
+	clone.combined = true
+
+	return &clone
+}
+
+type cloner struct {
+	clones map[*subSchema]*subSchema
+}
+
+// Returns the copy of a subSchema, copying it when first seen so that
+// recursive references and shared subSchemas are copied once
+func (c *cloner) clone(s *subSchema) *subSchema {
+
+	if s == nil {
+		return nil
+	}
+	if clone, ok := c.clones[s]; ok {
+		return clone
+	}
+
+	// the pointers to numbers, strings and booleans are shared : the setters
+	// replace them rather than writing through them
+	clone := &subSchema{}
+	*clone = *s
+	c.clones[s] = clone
+
+	clone.types.types = copyStrings(s.types.types)
+
+	clone.refSchema = c.clone(s.refSchema)
+
+	clone.parent = c.clone(s.parent)
+	clone.definitions = c.cloneMap(s.definitions)
+	clone.definitionsChildren = c.cloneList(s.definitionsChildren)
+	clone.itemsChildren = c.cloneList(s.itemsChildren)
+	clone.propertiesChildren = c.cloneList(s.propertiesChildren)
+
+	clone.required = copyStrings(s.required)
+	clone.anyFormat = copyStrings(s.anyFormat)
+
+	if s.dependencies != nil {
+		clone.dependencies = make(map[string]interface{}, len(s.dependencies))
+		for k, dependency := range s.dependencies {
+			clone.dependencies[k] = c.cloneBoolOrSchema(dependency)
+		}
+	}
+	clone.dependentSchemas = c.cloneMap(s.dependentSchemas)
+	clone.propertyNames = c.clone(s.propertyNames)
+	clone.additionalProperties = c.cloneBoolOrSchema(s.additionalProperties)
+	clone.patternProperties = c.cloneMap(s.patternProperties)
+
+	clone.additionalItems = c.cloneBoolOrSchema(s.additionalItems)
+
+	clone.enum = copyStrings(s.enum)
+
+	clone.oneOf = c.cloneList(s.oneOf)
+	clone.anyOf = c.cloneList(s.anyOf)
+	clone.allOf = c.cloneList(s.allOf)
+	clone.not = c.clone(s.not)
+
+	return clone
+}
+
+func (c *cloner) cloneList(list []*subSchema) []*subSchema {
+	if list == nil {
+		return nil
+	}
+	clones := make([]*subSchema, len(list))
+	for i, s := range list {
+		clones[i] = c.clone(s)
+	}
+	return clones
+}
+
+func (c *cloner) cloneMap(m map[string]*subSchema) map[string]*subSchema {
+	if m == nil {
+		return nil
+	}
+	clones := make(map[string]*subSchema, len(m))
+	for k, s := range m {
+		clones[k] = c.clone(s)
+	}
+	return clones
+}
+
+// Copies the value of a keyword accepting a boolean, a schema or an array of strings
+func (c *cloner) cloneBoolOrSchema(value interface{}) interface{} {
+	switch value := value.(type) {
+	case *subSchema:
+		return c.clone(value)
+	case []string:
+		return copyStrings(value)
+	}
+	return value
+}
+
+func copyStrings(list []string) []string {
+	if list == nil {
+		return nil
+	}
+	return append([]string(nil), list...)
+}
@@ -0,0 +1,93 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for the deep copy of schemas.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClone(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"size": {"minimum": 1},
+			"node": {"$ref": "#"}
+		},
+		"required": ["size"]
+	}`))
+	assert.Nil(t, err)
+
+	clone := schema.Clone()
+
+	size := clone.rootSchema.propertyChild("size")
+	size.SetMinimum(10)
+	assert.Nil(t, clone.rootSchema.AddRequired("node"))
+
+	// the whole tree is copied, referenced subSchemas included
+	assert.True(t, clone.rootSchema.propertyChild("node").refSchema != schema.rootSchema.propertyChild("node").refSchema)
+	assert.True(t, size.parent == clone.rootSchema)
+
+	document := NewStringLoader(`{"size": 5}`)
+
+	result, err := schema.Validate(document)
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	result, err = clone.Validate(document)
+	assert.Nil(t, err)
+	var contexts []string
+	for _, resultError := range result.Errors() {
+		contexts = append(contexts, resultError.Context.String())
+	}
+	assert.ElementsMatch(t, []string{"#/size", "#/node"}, contexts)
+
+	minimum, _ := schema.rootSchema.propertyChild("size").Minimum()
+	assert.Equal(t, float64(1), minimum)
+	assert.Equal(t, []string{"size"}, schema.rootSchema.required)
+}
+
+func TestCloneAddAnyOf(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"type": "object"}`))
+	assert.Nil(t, err)
+	ab, err := NewSchema(NewStringLoader(`{"properties": {"a": {"type": "string"}, "b": {"type": "string"}}}`))
+	assert.Nil(t, err)
+	c, err := NewSchema(NewStringLoader(`{"properties": {"c": {"type": "string"}}}`))
+	assert.Nil(t, err)
+
+	// the scores pick the subSchema matching the most properties
+	clone := schema.Clone()
+	clone.rootSchema.AddAnyOf(ab.rootSchema)
+	clone.rootSchema.AddAnyOf(c.rootSchema)
+
+	result, err := clone.Validate(NewStringLoader(`{"a": "x", "b": 1, "c": 1}`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, "#/b", result.Errors()[0].Context.String())
+	}
+}
@@ -0,0 +1,570 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      Serialization of compiled schemas, so they can be cached
+//                  and loaded back without fetching and resolving references.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+
+	"github.com/xeipuuv/gojsonreference"
+)
+
+// Version of the compiled form, bumped whenever it changes
+const compiledSchemaVersion = 2
+
+// The subSchema tree is flattened into a list, pointers between subSchemas
+// (children, parents and resolved references) becoming indexes in this list.
+type compiledSchema struct {
+	Version           int
+	DocumentReference string
+	Root              int
+	SubSchemas        []*compiledSubSchema
+}
+
+type compiledSubSchema struct {
+	Id          *string `json:",omitempty"`
+	Title       *string `json:",omitempty"`
+	Description *string `json:",omitempty"`
+	Comment     *string `json:",omitempty"`
+	Default     *string `json:",omitempty"`
+
+	Property string
+	Location string
+	Types    []string `json:",omitempty"`
+
+	Ref       *string `json:",omitempty"`
+	RefSchema *int    `json:",omitempty"`
+	SubSchema *string `json:",omitempty"`
+
+	Parent                      *int           `json:",omitempty"`
+	Definitions                 map[string]int `json:",omitempty"`
+	DefinitionsChildren         []int          `json:",omitempty"`
+	ItemsChildren               []int          `json:",omitempty"`
+	ItemsChildrenIsSingleSchema bool           `json:",omitempty"`
+	PropertiesChildren          []int          `json:",omitempty"`
+
+	MultipleOf       *float64 `json:",omitempty"`
+	Maximum          *float64 `json:",omitempty"`
+	ExclusiveMaximum *bool    `json:",omitempty"`
+	Minimum          *float64 `json:",omitempty"`
+	ExclusiveMinimum *bool    `json:",omitempty"`
+	NumberFormat     *string  `json:",omitempty"`
+	MaxDecimals      *int     `json:",omitempty"`
+
+	MinLength *int     `json:",omitempty"`
+	MaxLength *int     `json:",omitempty"`
+	Pattern   *string  `json:",omitempty"`
+	Format    *string  `json:",omitempty"`
+	AnyFormat []string `json:",omitempty"`
+
+	ContentEncoding  *string `json:",omitempty"`
+	ContentMediaType *string `json:",omitempty"`
+
+	MinProperties *int     `json:",omitempty"`
+	MaxProperties *int     `json:",omitempty"`
+	Required      []string `json:",omitempty"`
+
+	Dependencies         map[string]*compiledBoolOrSchema `json:",omitempty"`
+	DependentSchemas     map[string]int                   `json:",omitempty"`
+	PropertyNames        *int                             `json:",omitempty"`
+	AdditionalProperties *compiledBoolOrSchema            `json:",omitempty"`
+	PatternProperties    map[string]int                   `json:",omitempty"`
+
+	MinItems    *int  `json:",omitempty"`
+	MaxItems    *int  `json:",omitempty"`
+	UniqueItems *bool `json:",omitempty"`
+
+	ItemsBoolean *bool `json:",omitempty"`
+
+	Sorted   *string `json:",omitempty"`
+	SortedBy *string `json:",omitempty"`
+
+	PropertyOrder []string `json:",omitempty"`
+
+	ErrorMessage  *string           `json:",omitempty"`
+	ErrorMessages map[string]string `json:",omitempty"`
+
+	AdditionalItems *compiledBoolOrSchema `json:",omitempty"`
+
+	Enum []string `json:",omitempty"`
+
+	Discriminator *discriminator `json:",omitempty"`
+
+	OneOf []int `json:",omitempty"`
+	AnyOf []int `json:",omitempty"`
+	AllOf []int `json:",omitempty"`
+	Not   *int  `json:",omitempty"`
+
+	Keywords map[string]interface{} `json:",omitempty"`
+}
+
+// Holds the values of keywords accepting a boolean, a schema or an array of strings
+type compiledBoolOrSchema struct {
+	Bool    *bool    `json:",omitempty"`
+	Schema  *int     `json:",omitempty"`
+	Strings []string `json:",omitempty"`
+}
+
+// MarshalCompiled serializes the compiled schema, references already resolved.
+// The result can be loaded back with LoadCompiled, typically from a cache.
+func (d *Schema) MarshalCompiled() ([]byte, error) {
+
+	c := &compiler{indexes: make(map[*subSchema]int)}
+
+	compiled := compiledSchema{
+		Version:           compiledSchemaVersion,
+		DocumentReference: d.documentReference.String(),
+		Root:              c.index(d.rootSchema),
+	}
+
+	// compiling a subSchema may register new ones, hence the loop on the length
+	// maps are walked in the order of their keys so the output is deterministic
+	for i := 0; i < len(c.subSchemas); i++ {
+		compiled.SubSchemas = append(compiled.SubSchemas, c.compile(c.subSchemas[i]))
+	}
+
+	return json.Marshal(compiled)
+}
+
+// LoadCompiled loads a schema serialized by MarshalCompiled
+func LoadCompiled(data []byte) (*Schema, error) {
+
+	var compiled compiledSchema
+	err := json.Unmarshal(data, &compiled)
+	if err != nil {
+		return nil, err
+	}
+
+	if compiled.Version != compiledSchemaVersion {
+		return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_COMPILED_SCHEMA_VERSION, compiled.Version))
+	}
+
+	d := Schema{}
+	d.pool = newSchemaPool()
+	d.referencePool = newSchemaReferencePool()
+	d.documentReference, err = gojsonreference.NewJsonReference(compiled.DocumentReference)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &decompiler{compiled: compiled.SubSchemas, references: make(map[string]*gojsonreference.JsonReference)}
+	for range compiled.SubSchemas {
+		l.subSchemas = append(l.subSchemas, &subSchema{})
+	}
+
+	for i, cs := range compiled.SubSchemas {
+		err = l.decompile(cs, l.subSchemas[i])
+		if err != nil {
+			return nil, err
+		}
+		if cs.PropertyOrder != nil {
+			d.propertyOrdered = true
+		}
+		if cs.MaxDecimals != nil {
+			d.decimalsBounded = true
+		}
+		if len(cs.AnyOf) > 0 || len(cs.OneOf) > 0 {
+			d.combined = true
+		}
+	}
+
+	d.rootSchema, err = l.get(compiled.Root)
+	if err != nil {
+		return nil, err
+	}
+
+	return &d, nil
+}
+
+type compiler struct {
+	indexes    map[*subSchema]int
+	subSchemas []*subSchema
+}
+
+// Returns the index of a subSchema, registering it when first seen
+func (c *compiler) index(s *subSchema) int {
+	if i, ok := c.indexes[s]; ok {
+		return i
+	}
+	i := len(c.subSchemas)
+	c.indexes[s] = i
+	c.subSchemas = append(c.subSchemas, s)
+	return i
+}
+
+func (c *compiler) indexPointer(s *subSchema) *int {
+	if s == nil {
+		return nil
+	}
+	i := c.index(s)
+	return &i
+}
+
+func (c *compiler) indexList(list []*subSchema) []int {
+	var indexes []int
+	for _, s := range list {
+		indexes = append(indexes, c.index(s))
+	}
+	return indexes
+}
+
+func (c *compiler) boolOrSchema(value interface{}) *compiledBoolOrSchema {
+	switch value := value.(type) {
+	case bool:
+		return &compiledBoolOrSchema{Bool: &value}
+	case *subSchema:
+		return &compiledBoolOrSchema{Schema: c.indexPointer(value)}
+	case []string:
+		return &compiledBoolOrSchema{Strings: value}
+	}
+	return nil
+}
+
+func (c *compiler) compile(s *subSchema) *compiledSubSchema {
+
+	cs := &compiledSubSchema{
+		Id:          s.id,
+		Title:       s.title,
+		Description: s.description,
+		Comment:     s.comment,
+		Default:     s.defaultValue,
+
+		Property: s.property,
+		Location: s.location,
+		Types:    s.types.types,
+
+		RefSchema: c.indexPointer(s.refSchema),
+
+		Parent:                      c.indexPointer(s.parent),
+		DefinitionsChildren:         c.indexList(s.definitionsChildren),
+		ItemsChildren:               c.indexList(s.itemsChildren),
+		ItemsChildrenIsSingleSchema: s.itemsChildrenIsSingleSchema,
+		PropertiesChildren:          c.indexList(s.propertiesChildren),
+
+		MultipleOf:       s.multipleOf,
+		Maximum:          s.maximum,
+		ExclusiveMaximum: s.exclusiveMaximum,
+		Minimum:          s.minimum,
+		ExclusiveMinimum: s.exclusiveMinimum,
+		NumberFormat:     s.numberFormat,
+		MaxDecimals:      s.maxDecimals,
+
+		MinLength: s.minLength,
+		MaxLength: s.maxLength,
+		Format:    s.format,
+		AnyFormat: s.anyFormat,
+
+		ContentEncoding:  s.contentEncoding,
+		ContentMediaType: s.contentMediaType,
+
+		MinProperties: s.minProperties,
+		MaxProperties: s.maxProperties,
+		Required:      s.required,
+
+		PropertyNames:        c.indexPointer(s.propertyNames),
+		AdditionalProperties: c.boolOrSchema(s.additionalProperties),
+
+		MinItems:    s.minItems,
+		MaxItems:    s.maxItems,
+		UniqueItems: s.uniqueItems,
+
+		ItemsBoolean: s.itemsBoolean,
+
+		Sorted:   s.sorted,
+		SortedBy: s.sortedBy,
+
+		PropertyOrder: s.propertyOrder,
+
+		ErrorMessage:  s.errorMessage,
+		ErrorMessages: s.errorMessages,
+
+		AdditionalItems: c.boolOrSchema(s.additionalItems),
+
+		Enum: s.enum,
+
+		Discriminator: s.discriminator,
+
+		OneOf: c.indexList(s.oneOf),
+		AnyOf: c.indexList(s.anyOf),
+		AllOf: c.indexList(s.allOf),
+		Not:   c.indexPointer(s.not),
+
+		Keywords: s.keywordValues,
+	}
+
+	if s.ref != nil {
+		ref := s.ref.String()
+		cs.Ref = &ref
+	}
+	if s.subSchema != nil {
+		subSchemaRef := s.subSchema.String()
+		cs.SubSchema = &subSchemaRef
+	}
+	if s.pattern != nil {
+		pattern := s.pattern.String()
+		cs.Pattern = &pattern
+	}
+
+	if s.definitions != nil {
+		cs.Definitions = make(map[string]int)
+		for _, k := range sortedKeys(s.definitions) {
+			cs.Definitions[k] = c.index(s.definitions[k])
+		}
+	}
+	if s.dependentSchemas != nil {
+		cs.DependentSchemas = make(map[string]int)
+		for _, k := range sortedKeys(s.dependentSchemas) {
+			cs.DependentSchemas[k] = c.index(s.dependentSchemas[k])
+		}
+	}
+	if s.patternProperties != nil {
+		cs.PatternProperties = make(map[string]int)
+		for _, k := range sortedKeys(s.patternProperties) {
+			cs.PatternProperties[k] = c.index(s.patternProperties[k])
+		}
+	}
+	if s.dependencies != nil {
+		cs.Dependencies = make(map[string]*compiledBoolOrSchema)
+		for _, k := range sortedKeys(s.dependencies) {
+			cs.Dependencies[k] = c.boolOrSchema(s.dependencies[k])
+		}
+	}
+
+	return cs
+}
+
+type decompiler struct {
+	compiled   []*compiledSubSchema
+	subSchemas []*subSchema
+	references map[string]*gojsonreference.JsonReference
+}
+
+func (l *decompiler) get(i int) (*subSchema, error) {
+	if i < 0 || i >= len(l.subSchemas) {
+		return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_INTERNAL, "compiled schema index out of range"))
+	}
+	return l.subSchemas[i], nil
+}
+
+func (l *decompiler) getPointer(i *int) (*subSchema, error) {
+	if i == nil {
+		return nil, nil
+	}
+	return l.get(*i)
+}
+
+func (l *decompiler) getList(indexes []int) ([]*subSchema, error) {
+	var list []*subSchema
+	for _, i := range indexes {
+		s, err := l.get(i)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, s)
+	}
+	return list, nil
+}
+
+func (l *decompiler) getMap(indexes map[string]int) (map[string]*subSchema, error) {
+	if indexes == nil {
+		return nil, nil
+	}
+	m := make(map[string]*subSchema)
+	for k, i := range indexes {
+		s, err := l.get(i)
+		if err != nil {
+			return nil, err
+		}
+		m[k] = s
+	}
+	return m, nil
+}
+
+func (l *decompiler) boolOrSchema(value *compiledBoolOrSchema) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+	if value.Bool != nil {
+		return *value.Bool, nil
+	}
+	if value.Schema != nil {
+		return l.get(*value.Schema)
+	}
+	return value.Strings, nil
+}
+
+// References are shared between the subSchemas having the same one
+func (l *decompiler) reference(ref *string) (*gojsonreference.JsonReference, error) {
+	if ref == nil {
+		return nil, nil
+	}
+	if r, ok := l.references[*ref]; ok {
+		return r, nil
+	}
+	r, err := gojsonreference.NewJsonReference(*ref)
+	if err != nil {
+		return nil, err
+	}
+	l.references[*ref] = &r
+	return &r, nil
+}
+
+func (l *decompiler) decompile(cs *compiledSubSchema, s *subSchema) error {
+
+	var err error
+
+	s.id = cs.Id
+	s.title = cs.Title
+	s.description = cs.Description
+	s.comment = cs.Comment
+	s.defaultValue = cs.Default
+
+	s.property = cs.Property
+	s.location = cs.Location
+	s.types.types = cs.Types
+
+	if s.ref, err = l.reference(cs.Ref); err != nil {
+		return err
+	}
+	if s.refSchema, err = l.getPointer(cs.RefSchema); err != nil {
+		return err
+	}
+	if s.subSchema, err = l.reference(cs.SubSchema); err != nil {
+		return err
+	}
+
+	if s.parent, err = l.getPointer(cs.Parent); err != nil {
+		return err
+	}
+	if s.definitions, err = l.getMap(cs.Definitions); err != nil {
+		return err
+	}
+	if s.definitionsChildren, err = l.getList(cs.DefinitionsChildren); err != nil {
+		return err
+	}
+	if s.itemsChildren, err = l.getList(cs.ItemsChildren); err != nil {
+		return err
+	}
+	s.itemsChildrenIsSingleSchema = cs.ItemsChildrenIsSingleSchema
+	if s.propertiesChildren, err = l.getList(cs.PropertiesChildren); err != nil {
+		return err
+	}
+
+	s.multipleOf = cs.MultipleOf
+	s.maximum = cs.Maximum
+	s.exclusiveMaximum = cs.ExclusiveMaximum
+	s.minimum = cs.Minimum
+	s.exclusiveMinimum = cs.ExclusiveMinimum
+	s.numberFormat = cs.NumberFormat
+	s.maxDecimals = cs.MaxDecimals
+
+	s.minLength = cs.MinLength
+	s.maxLength = cs.MaxLength
+	if cs.Pattern != nil {
+		if s.pattern, err = regexp.Compile(*cs.Pattern); err != nil {
+			return errors.New(fmt.Sprintf(ERROR_MESSAGE_INVALID_REGEX_PATTERN, *cs.Pattern))
+		}
+	}
+	s.format = cs.Format
+	s.anyFormat = cs.AnyFormat
+	s.contentEncoding = cs.ContentEncoding
+	s.contentMediaType = cs.ContentMediaType
+
+	s.minProperties = cs.MinProperties
+	s.maxProperties = cs.MaxProperties
+	s.required = cs.Required
+
+	if cs.Dependencies != nil {
+		s.dependencies = make(map[string]interface{})
+		for k, dependency := range cs.Dependencies {
+			if s.dependencies[k], err = l.boolOrSchema(dependency); err != nil {
+				return err
+			}
+		}
+	}
+	if s.dependentSchemas, err = l.getMap(cs.DependentSchemas); err != nil {
+		return err
+	}
+	if s.propertyNames, err = l.getPointer(cs.PropertyNames); err != nil {
+		return err
+	}
+	if s.additionalProperties, err = l.boolOrSchema(cs.AdditionalProperties); err != nil {
+		return err
+	}
+	if s.patternProperties, err = l.getMap(cs.PatternProperties); err != nil {
+		return err
+	}
+
+	s.minItems = cs.MinItems
+	s.maxItems = cs.MaxItems
+	s.uniqueItems = cs.UniqueItems
+
+	s.itemsBoolean = cs.ItemsBoolean
+
+	s.sorted = cs.Sorted
+	s.sortedBy = cs.SortedBy
+	s.propertyOrder = cs.PropertyOrder
+	s.errorMessage = cs.ErrorMessage
+	s.errorMessages = cs.ErrorMessages
+
+	if s.additionalItems, err = l.boolOrSchema(cs.AdditionalItems); err != nil {
+		return err
+	}
+
+	s.enum = cs.Enum
+
+	s.discriminator = cs.Discriminator
+
+	if s.oneOf, err = l.getList(cs.OneOf); err != nil {
+		return err
+	}
+	if s.anyOf, err = l.getList(cs.AnyOf); err != nil {
+		return err
+	}
+	if s.allOf, err = l.getList(cs.AllOf); err != nil {
+		return err
+	}
+	if s.not, err = l.getPointer(cs.Not); err != nil {
+		return err
+	}
+
+	// the keywords given by SchemaLoaderOptions are unknown here, dropping
+	// them would silently loosen the schema
+	for _, keyword := range sortedKeys(cs.Keywords) {
+		validator, ok := keywordValidators[keyword]
+		if !ok {
+			return errors.New(fmt.Sprintf(ERROR_MESSAGE_KEYWORD_X_IS_NOT_REGISTERED, keyword))
+		}
+		if err = s.setKeyword(keyword, cs.Keywords[keyword], validator); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,79 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for compiled schemas serialization.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompiledSchemaRoundTrip(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"definitions": {
+			"node": {
+				"type": "object",
+				"properties": {
+					"name": {"type": "string", "pattern": "^[a-z]+$"},
+					"children": {"type": "array", "items": {"$ref": "#/definitions/node"}}
+				},
+				"required": ["name"],
+				"additionalProperties": false,
+				"dependencies": {"children": ["name"]}
+			}
+		},
+		"$ref": "#/definitions/node"
+	}`))
+	assert.Nil(t, err)
+
+	compiled, err := schema.MarshalCompiled()
+	assert.Nil(t, err)
+
+	loaded, err := LoadCompiled(compiled)
+	assert.Nil(t, err)
+
+	documents := []string{
+		`{"name": "root", "children": [{"name": "leaf"}]}`,
+		`{"name": "root", "children": [{"name": "Leaf", "extra": 1}, {}]}`,
+		`[]`,
+	}
+	for _, document := range documents {
+		expected, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		given, err := loaded.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, expected.Errors(), given.Errors(), document)
+	}
+
+	// compiling the loaded schema again gives the same blob
+	recompiled, err := loaded.MarshalCompiled()
+	assert.Nil(t, err)
+	assert.Equal(t, string(compiled), string(recompiled))
+
+	_, err = LoadCompiled([]byte(`{"Version": 0}`))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,87 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      Validation of streams of documents by a pool of goroutines.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"sync"
+)
+
+// IndexedResult is the validation of one of the documents given to
+// ValidateConcurrent, Index being its position in the input channel.
+type IndexedResult struct {
+	Index  int
+	Result *Result
+	Err    error
+}
+
+// ValidateConcurrent validates the documents received from loaders with
+// workers goroutines, a schema being safe to share once parsed. The results
+// are sent as they are ready, in no particular order, and the returned channel
+// is closed once loaders is closed and all its documents are validated.
+// Nothing is buffered : the workers wait for the results to be received, so
+// the caller must drain the channel.
+func (v *Schema) ValidateConcurrent(loaders <-chan JSONLoader, workers int) <-chan IndexedResult {
+
+	if workers < 1 {
+		workers = 1
+	}
+
+	type job struct {
+		index  int
+		loader JSONLoader
+	}
+
+	jobs := make(chan job)
+	results := make(chan IndexedResult)
+
+	go func() {
+		index := 0
+		for l := range loaders {
+			jobs <- job{index: index, loader: l}
+			index++
+		}
+		close(jobs)
+	}()
+
+	var wg sync.WaitGroup
+	wg.Add(workers)
+	for i := 0; i < workers; i++ {
+		go func() {
+			defer wg.Done()
+			for j := range jobs {
+				result, err := v.Validate(j.loader)
+				results <- IndexedResult{Index: j.index, Result: result, Err: err}
+			}
+		}()
+	}
+
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+
+	return results
+}
@@ -0,0 +1,72 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for the concurrent validation of streams of documents.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateConcurrent(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"type": "integer", "multipleOf": 2}`))
+	assert.Nil(t, err)
+
+	const nbDocuments = 1000
+
+	loaders := make(chan JSONLoader)
+	go func() {
+		for i := 0; i < nbDocuments; i++ {
+			loaders <- NewStringLoader(strconv.Itoa(i))
+		}
+		// a document that cannot be loaded
+		loaders <- NewStringLoader(`{`)
+		close(loaders)
+	}()
+
+	seen := make(map[int]bool)
+	for indexed := range schema.ValidateConcurrent(loaders, 8) {
+		assert.False(t, seen[indexed.Index], "index %d", indexed.Index)
+		seen[indexed.Index] = true
+		if indexed.Index == nbDocuments {
+			assert.NotNil(t, indexed.Err)
+			continue
+		}
+		if assert.Nil(t, indexed.Err) {
+			assert.Equal(t, indexed.Index%2 == 0, indexed.Result.Valid(), "index %d", indexed.Index)
+		}
+	}
+	assert.Len(t, seen, nbDocuments+1)
+
+	// no document, no worker count
+	loaders = make(chan JSONLoader)
+	close(loaders)
+	for range schema.ValidateConcurrent(loaders, 0) {
+		t.Error("no result expected")
+	}
+}
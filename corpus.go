@@ -0,0 +1,128 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      Statistics on the validation of a corpus of documents.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"sort"
+)
+
+// CorpusReport sums up the validation of many documents, see ValidateCorpus.
+type CorpusReport struct {
+	Documents    int // documents given
+	Valid        int // documents valid against the schema
+	Invalid      int // documents having at least one error
+	LoadFailures int // documents that could not be loaded, neither valid nor invalid
+
+	Keywords []CorpusCount // keywords of the errors, the most common first
+	Paths    []CorpusCount // paths of the errors, ex #/items/0/name, the most common first
+}
+
+// CorpusCount tells how often a keyword or a path fails across a corpus.
+type CorpusCount struct {
+	Name      string // keyword or path
+	Documents int    // invalid documents having at least one such error
+	Errors    int    // errors over all the documents
+}
+
+// ValidateCorpus validates each document and counts the errors by keyword and
+// by path, turning the results into an overview of the health of a dataset.
+// The counts are ordered by number of documents, then of errors, then by
+// name. The results themselves are not kept.
+func (v *Schema) ValidateCorpus(loaders []JSONLoader) CorpusReport {
+
+	report := CorpusReport{Documents: len(loaders)}
+	keywords := newCorpusCounter()
+	paths := newCorpusCounter()
+
+	for _, l := range loaders {
+		result, err := v.Validate(l)
+		if err != nil {
+			report.LoadFailures++
+			continue
+		}
+		if result.Valid() {
+			report.Valid++
+			continue
+		}
+		report.Invalid++
+		for _, rerr := range result.Errors() {
+			keywords.add(rerr.Reason)
+			paths.add(rerr.Context.String())
+		}
+		keywords.endDocument()
+		paths.endDocument()
+	}
+
+	report.Keywords = keywords.counts()
+	report.Paths = paths.counts()
+
+	return report
+}
+
+// Counts the errors by name, and the documents they are found in
+type corpusCounter struct {
+	byName   map[string]*CorpusCount
+	document map[string]bool // names seen in the current document
+}
+
+func newCorpusCounter() *corpusCounter {
+	return &corpusCounter{byName: make(map[string]*CorpusCount), document: make(map[string]bool)}
+}
+
+func (c *corpusCounter) add(name string) {
+	count, ok := c.byName[name]
+	if !ok {
+		count = &CorpusCount{Name: name}
+		c.byName[name] = count
+	}
+	count.Errors++
+	if !c.document[name] {
+		c.document[name] = true
+		count.Documents++
+	}
+}
+
+func (c *corpusCounter) endDocument() {
+	c.document = make(map[string]bool)
+}
+
+// Returns the counts, the most common first
+func (c *corpusCounter) counts() []CorpusCount {
+	counts := make([]CorpusCount, 0, len(c.byName))
+	for _, count := range c.byName {
+		counts = append(counts, *count)
+	}
+	sort.Slice(counts, func(i, j int) bool {
+		if counts[i].Documents != counts[j].Documents {
+			return counts[i].Documents > counts[j].Documents
+		}
+		if counts[i].Errors != counts[j].Errors {
+			return counts[i].Errors > counts[j].Errors
+		}
+		return counts[i].Name < counts[j].Name
+	})
+	return counts
+}
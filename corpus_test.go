@@ -0,0 +1,69 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for the validation of a corpus of documents.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateCorpus(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"type": "object",
+		"required": ["id"],
+		"properties": {"id": {"type": "integer"}, "tags": {"items": {"type": "string"}}}
+	}`))
+	assert.Nil(t, err)
+
+	report := schema.ValidateCorpus([]JSONLoader{
+		NewStringLoader(`{"id": 1}`),
+		NewStringLoader(`{"id": "a", "tags": [1, 2]}`),
+		NewStringLoader(`{"tags": ["a", 3]}`),
+		NewStringLoader(`{"id": "b"}`),
+		NewStringLoader(`[]`),
+		NewStringLoader(`{`),
+	})
+
+	assert.Equal(t, 6, report.Documents)
+	assert.Equal(t, 1, report.Valid)
+	assert.Equal(t, 4, report.Invalid)
+	assert.Equal(t, 1, report.LoadFailures)
+	assert.Equal(t, []CorpusCount{
+		{Name: KEY_TYPE, Documents: 4, Errors: 6},
+		{Name: KEY_REQUIRED, Documents: 1, Errors: 1},
+	}, report.Keywords)
+	assert.Equal(t, []CorpusCount{
+		{Name: "#/id", Documents: 3, Errors: 3},
+		{Name: "#/tags/1", Documents: 2, Errors: 2},
+		{Name: "#", Documents: 1, Errors: 1},
+		{Name: "#/tags/0", Documents: 1, Errors: 1},
+	}, report.Paths)
+
+	report = schema.ValidateCorpus(nil)
+	assert.Equal(t, CorpusReport{Keywords: []CorpusCount{}, Paths: []CorpusCount{}}, report)
+}
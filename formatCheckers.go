@@ -0,0 +1,176 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      Checkers of the values of the format keyword.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"net"
+	"net/mail"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// FormatChecker tells whether a string is of a format
+type FormatChecker interface {
+	IsFormat(input string) bool
+}
+
+// FormatCheckerChain holds the checkers of the known formats, by name
+type FormatCheckerChain struct {
+	formatters map[string]FormatChecker
+}
+
+// FormatCheckers holds the checkers used by the validations. Formats without
+// a checker are not checked. Add custom formats before validating, the chain
+// is not safe for concurrent modification.
+var FormatCheckers = FormatCheckerChain{
+	formatters: map[string]FormatChecker{
+		"date-time":     DateTimeFormatChecker{},
+		"email":         EmailFormatChecker{},
+		"hostname":      HostnameFormatChecker{},
+		"ipv4":          IPV4FormatChecker{},
+		"ipv6":          IPV6FormatChecker{},
+		"uri":           URIFormatChecker{},
+		"uri-reference": URIReferenceFormatChecker{},
+		"uri-template":  URITemplateFormatChecker{},
+	},
+}
+
+// Add registers the checker of a format, replacing any previous one
+func (c *FormatCheckerChain) Add(name string, f FormatChecker) *FormatCheckerChain {
+	c.formatters[name] = f
+	return c
+}
+
+// Remove unregisters the checker of a format
+func (c *FormatCheckerChain) Remove(name string) *FormatCheckerChain {
+	delete(c.formatters, name)
+	return c
+}
+
+// Has tells whether a format has a checker
+func (c *FormatCheckerChain) Has(name string) bool {
+	_, ok := c.formatters[name]
+	return ok
+}
+
+// IsFormat tells whether input is of the format, true for unknown formats
+func (c *FormatCheckerChain) IsFormat(name string, input string) bool {
+
+	f, ok := c.formatters[name]
+	if !ok {
+		return true
+	}
+
+	return f.IsFormat(input)
+}
+
+// date-time, as defined by RFC 3339 section 5.6
+type DateTimeFormatChecker struct{}
+
+func (f DateTimeFormatChecker) IsFormat(input string) bool {
+	_, err := time.Parse(time.RFC3339Nano, strings.ToUpper(input))
+	return err == nil
+}
+
+// email, a mailbox address as defined by RFC 5322 section 3.4.1
+type EmailFormatChecker struct{}
+
+func (f EmailFormatChecker) IsFormat(input string) bool {
+	address, err := mail.ParseAddress(input)
+	return err == nil && address.Address == input
+}
+
+// hostname, as defined by RFC 1034 section 3.1
+type HostnameFormatChecker struct{}
+
+var hostnameLabelRegexp = regexp.MustCompile(`^[a-zA-Z0-9]([a-zA-Z0-9-]{0,61}[a-zA-Z0-9])?$`)
+
+func (f HostnameFormatChecker) IsFormat(input string) bool {
+
+	if len(input) == 0 || len(input) > 255 {
+		return false
+	}
+
+	for _, label := range strings.Split(input, ".") {
+		if !hostnameLabelRegexp.MatchString(label) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// ipv4, dotted-quad notation as defined by RFC 2673 section 3.2
+type IPV4FormatChecker struct{}
+
+func (f IPV4FormatChecker) IsFormat(input string) bool {
+	ip := net.ParseIP(input)
+	return ip != nil && ip.To4() != nil && !strings.Contains(input, ":")
+}
+
+// ipv6, as defined by RFC 2373 section 2.2
+type IPV6FormatChecker struct{}
+
+func (f IPV6FormatChecker) IsFormat(input string) bool {
+	ip := net.ParseIP(input)
+	return ip != nil && strings.Contains(input, ":")
+}
+
+// uri, an absolute URI as defined by RFC 3986
+type URIFormatChecker struct{}
+
+func (f URIFormatChecker) IsFormat(input string) bool {
+	u, err := url.Parse(input)
+	return err == nil && u.Scheme != "" && !strings.ContainsAny(input, " \t\n")
+}
+
+// uri-reference, a URI or a relative reference as defined by RFC 3986 section 4.1
+type URIReferenceFormatChecker struct{}
+
+func (f URIReferenceFormatChecker) IsFormat(input string) bool {
+	_, err := url.Parse(input)
+	return err == nil && !strings.ContainsAny(input, " \t\n")
+}
+
+// uri-template, as defined by RFC 6570 section 2 : literals and expressions
+// such as {var}, {+path}, {?x,y} or {list*}. The operators reserved for
+// future extensions are rejected.
+type URITemplateFormatChecker struct{}
+
+var uriTemplateRegexp = func() *regexp.Regexp {
+	pctEncoded := `%[0-9A-Fa-f]{2}`
+	literal := "[^\\x00-\\x20\"'%<>\\\\^`{|}\\x7f]|" + pctEncoded
+	varchar := `(?:[A-Za-z0-9_]|` + pctEncoded + `)`
+	varspec := varchar + `(?:\.?` + varchar + `)*(?::[1-9][0-9]{0,3}|\*)?`
+	expression := `\{[+#./;?&]?` + varspec + `(?:,` + varspec + `)*\}`
+	return regexp.MustCompile(`^(?:` + literal + `|` + expression + `)*$`)
+}()
+
+func (f URITemplateFormatChecker) IsFormat(input string) bool {
+	return uriTemplateRegexp.MatchString(input)
+}
@@ -0,0 +1,99 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for the format checkers.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatCheckers(t *testing.T) {
+
+	cases := map[string]map[string]bool{
+		"date-time": {"2013-08-08T10:00:00Z": true, "2013-08-08t10:00:00.123+02:00": true, "2013-08-08": false, "08/08/2013 10:00": false},
+		"email":     {"joe@example.com": true, "Joe <joe@example.com>": false, "joe": false},
+		"hostname":  {"example.com": true, "a-b.example": true, "-a.example": false, "a..b": false, "": false},
+		"ipv4":      {"192.168.0.1": true, "256.0.0.1": false, "::1": false},
+		"ipv6":      {"::1": true, "fe80::1:2": true, "192.168.0.1": false, "12345::": false},
+		"uri":       {"http://example.com/a?b#c": true, "urn:isbn:0451450523": true, "/relative": false, "http://exa mple.com": false},
+		"uri-reference": {
+			"http://example.com/a?b#c": true, "/relative": true, "../up?q=1": true, "#fragment": true, "": true,
+			"http://exa mple.com": false, "%zz": false,
+		},
+		"uri-template": {
+			"http://example.com/a": true, "/orders{/id}": true, "/search{?q,page,size}": true, "{+path}/here": true,
+			"{#section}": true, "/users/{user.id}{;list*}": true, "{var:30}": true, "http://example.com/%C3%A9": true,
+			"/orders{/id": false, "{}": false, "{=x}": false, "{var:0}": false, "{a b}": false, "/a b": false, "/%zz": false,
+		},
+	}
+
+	for format, inputs := range cases {
+		for input, valid := range inputs {
+			assert.Equal(t, valid, FormatCheckers.IsFormat(format, input), format+" "+input)
+		}
+	}
+
+	assert.True(t, FormatCheckers.IsFormat("unknown", "anything"))
+}
+
+type evenLengthFormatChecker struct{}
+
+func (f evenLengthFormatChecker) IsFormat(input string) bool {
+	return len(input)%2 == 0
+}
+
+func TestStrictFormat(t *testing.T) {
+
+	FormatCheckers.Add("even", evenLengthFormatChecker{})
+	defer FormatCheckers.Remove("even")
+	assert.True(t, FormatCheckers.Has("even"))
+
+	schema, err := NewSchema(NewStringLoader(`{"properties": {"ip": {"format": "ipv4"}, "code": {"format": "even"}, "n": {"format": "ipv4"}}}`))
+	assert.Nil(t, err)
+
+	document := NewStringLoader(`{"ip": "localhost", "code": "abc", "n": 12}`)
+
+	result, err := schema.Validate(document)
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+	var annotated []string
+	for _, annotation := range result.Annotations() {
+		assert.Equal(t, KEY_FORMAT, annotation.Reason)
+		annotated = append(annotated, annotation.Context.String())
+	}
+	assert.ElementsMatch(t, []string{"#/ip", "#/code"}, annotated)
+
+	result, err = schema.ValidateWithOptions(document, ValidateOptions{StrictFormat: true})
+	assert.Nil(t, err)
+	assert.Empty(t, result.Annotations())
+	var failed []string
+	for _, resultError := range result.Errors() {
+		assert.Equal(t, KEY_FORMAT, resultError.Reason)
+		failed = append(failed, resultError.Context.String())
+	}
+	assert.ElementsMatch(t, []string{"#/ip", "#/code"}, failed)
+}
@@ -0,0 +1,124 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      Cache of the documents loaded over HTTP, revalidated with conditional GETs.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// HTTPCache keeps the documents that the reference loaders given it by
+// LoaderOptions.HTTPCache load over HTTP, along with their ETag and
+// Last-Modified headers. A cached document is revalidated each time it is
+// loaded : the request carries If-None-Match and If-Modified-Since, and a
+// 304 Not Modified response serves the cached copy without downloading it
+// again. When the server fails, with an error status or no response, the
+// cached copy is served. The documents sent without ETag nor Last-Modified
+// are not cached. A cache is safe for concurrent use.
+type HTTPCache struct {
+
+	// Sends the requests, http.DefaultClient when nil
+	Client *http.Client
+
+	lock      sync.Mutex
+	documents map[string]*httpCachedDocument
+}
+
+type httpCachedDocument struct {
+	body         []byte
+	etag         string
+	lastModified string
+}
+
+func NewHTTPCache() *HTTPCache {
+	return &HTTPCache{documents: make(map[string]*httpCachedDocument)}
+}
+
+// Returns the body of the document at an address, from the cache when the
+// server tells it did not change
+func (c *HTTPCache) get(address string) ([]byte, error) {
+
+	c.lock.Lock()
+	cached := c.documents[address]
+	c.lock.Unlock()
+
+	request, err := http.NewRequest(http.MethodGet, address, nil)
+	if err != nil {
+		return nil, err
+	}
+	if cached != nil {
+		if cached.etag != "" {
+			request.Header.Set("If-None-Match", cached.etag)
+		}
+		if cached.lastModified != "" {
+			request.Header.Set("If-Modified-Since", cached.lastModified)
+		}
+	}
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	response, err := client.Do(request)
+	if err != nil {
+		if cached != nil {
+			return cached.body, nil
+		}
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	switch {
+
+	case response.StatusCode == http.StatusOK:
+		body, err := ioutil.ReadAll(response.Body)
+		if err != nil {
+			return nil, err
+		}
+		document := &httpCachedDocument{body: body, etag: response.Header.Get("ETag"), lastModified: response.Header.Get("Last-Modified")}
+		c.lock.Lock()
+		if document.etag != "" || document.lastModified != "" {
+			c.documents[address] = document
+		} else {
+			delete(c.documents, address)
+		}
+		c.lock.Unlock()
+		return body, nil
+
+	// a 304 is only expected for a cached document
+	case response.StatusCode == http.StatusNotModified && cached != nil:
+		return cached.body, nil
+
+	// the server failing does not make the cached copy stale
+	case response.StatusCode >= http.StatusInternalServerError && cached != nil:
+		return cached.body, nil
+	}
+
+	return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_GET_HTTP_BAD_STATUS, response.StatusCode))
+}
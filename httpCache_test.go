@@ -0,0 +1,115 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for the cache of the documents loaded over HTTP.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPCache(t *testing.T) {
+
+	etag := `"v1"`
+	document := `{"type": "string"}`
+	status := 0
+	var downloads, requests int
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if status != 0 {
+			w.WriteHeader(status)
+			return
+		}
+		if etag != "" && r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if etag != "" {
+			w.Header().Set("ETag", etag)
+		}
+		downloads++
+		w.Write([]byte(document))
+	}))
+	defer server.Close()
+
+	cache := NewHTTPCache()
+	load := func() (*Schema, error) {
+		return NewSchema(NewReferenceLoaderWithOptions(server.URL+"/a.json", LoaderOptions{HTTPCache: cache}))
+	}
+
+	// revalidated, not downloaded again
+	for i := 0; i < 3; i++ {
+		_, err := load()
+		assert.Nil(t, err)
+	}
+	assert.Equal(t, 3, requests)
+	assert.Equal(t, 1, downloads)
+
+	// changed
+	etag, document = `"v2"`, `{"type": "integer"}`
+	schema, err := load()
+	assert.Nil(t, err)
+	assert.Equal(t, 2, downloads)
+	result, err := schema.Validate(NewStringLoader(`1`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	// the cached copy outlives the failures of the server
+	status = http.StatusServiceUnavailable
+	schema, err = load()
+	assert.Nil(t, err)
+	result, err = schema.Validate(NewStringLoader(`1`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	status = http.StatusNotFound
+	_, err = load()
+	assert.EqualError(t, err, "Could not read schema from HTTP, response status is 404")
+
+	// through the $ref of a schema loaded with the cache
+	status = 0
+	requests = 0
+	_, err = NewSchema(NewStringLoaderWithOptions(`{"$ref": "`+server.URL+`/a.json"}`, LoaderOptions{HTTPCache: cache}))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, requests)
+	assert.Equal(t, 2, downloads)
+
+	// a 304 is unexpected for the documents not cached
+	status = http.StatusNotModified
+	_, err = NewSchema(NewReferenceLoaderWithOptions(server.URL+"/b.json", LoaderOptions{HTTPCache: cache}))
+	assert.EqualError(t, err, "Could not read schema from HTTP, response status is 304")
+
+	// nor are the documents without ETag nor Last-Modified cached
+	status, etag = 0, ""
+	_, err = load()
+	assert.Nil(t, err)
+	status = http.StatusServiceUnavailable
+	_, err = load()
+	assert.EqualError(t, err, "Could not read schema from HTTP, response status is 503")
+}
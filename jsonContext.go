@@ -39,7 +39,11 @@ func NewJSONContext(head string, tail *JSONContext) *JSONContext {
 // String displays the context in reverse.
 // This plays well with the data structure's persistent nature with
 // Cons and a json document's tree structure.
+// A nil context, as used when ValidateOptions.IsValid is set, displays as "".
 func (c *JSONContext) String() string {
+	if c == nil {
+		return ""
+	}
 	byteArr := make([]byte, 0, c.stringLen())
 	buf := bytes.NewBuffer(byteArr)
 	c.writeStringToBuffer(buf)
@@ -65,3 +69,29 @@ func (c *JSONContext) writeStringToBuffer(buf *bytes.Buffer) {
 
 	buf.WriteString(c.head)
 }
+
+// Segments returns the keys and indexes leading to the node, from the root to
+// the node itself, as raw strings. The root element of the context, "#" for the
+// contexts built while validating, is left out so the segments can be used to
+// walk into the document.
+func (c *JSONContext) Segments() []string {
+
+	if c == nil || c.tail == nil {
+		return []string{}
+	}
+
+	return append(c.tail.Segments(), c.head)
+}
+
+// Pointer returns the JSON pointer to the node, as defined by RFC 6901,
+// ex /items/0/a~1b. It is "" for the root, and for a nil context.
+func (c *JSONContext) Pointer() string {
+
+	var buf bytes.Buffer
+	for _, segment := range c.Segments() {
+		buf.WriteString("/")
+		buf.WriteString(escapeJsonPointerToken(segment))
+	}
+
+	return buf.String()
+}
@@ -0,0 +1,56 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for the json context.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestJSONContextSegments(t *testing.T) {
+
+	root := NewJSONContext(STRING_CONTEXT_ROOT, nil)
+	assert.Equal(t, []string{}, root.Segments())
+
+	context := NewJSONContext("a/b", NewJSONContext("0", NewJSONContext("items", root)))
+	assert.Equal(t, []string{"items", "0", "a/b"}, context.Segments())
+	assert.Equal(t, "#/items/0/a/b", context.String())
+	assert.Equal(t, "/items/0/a~1b", context.Pointer())
+	assert.Equal(t, "", root.Pointer())
+
+	var none *JSONContext
+	assert.Equal(t, []string{}, none.Segments())
+
+	result, err := Validate(
+		NewStringLoader(`{"items": {"properties": {"a.b": {"type": "string"}}}}`),
+		NewStringLoader(`[{}, {"a.b": 1}]`),
+	)
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, []string{"1", "a.b"}, result.Errors()[0].Context.Segments())
+	}
+}
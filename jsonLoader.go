@@ -27,11 +27,15 @@
 package gojsonschema
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
+	"reflect"
+	"strconv"
 	"strings"
 
 	"github.com/xeipuuv/gojsonreference"
@@ -42,14 +46,199 @@ import (
 type JSONLoader interface {
 	jsonSource() interface{}
 	loadJSON() (interface{}, error)
-	loadSchema() (*Schema, error)
+	loadSchema(options SchemaLoaderOptions) (*Schema, error)
+}
+
+// Options of the loaders reading JSON text, the reference and string loaders
+
+type LoaderOptions struct {
+
+	// Fails the loading of a JSON text holding an object with a duplicated key,
+	// which encoding/json silently resolves by keeping the last value.
+	ForbidDuplicateKeys bool
+
+	// Loads the documents over HTTP through a cache, revalidating them with
+	// conditional GETs, see HTTPCache. The documents of the $ref of a schema
+	// loaded with these options go through the cache too.
+	HTTPCache *HTTPCache
+}
+
+// Decodes a JSON text read by a loader
+func decodeLoadedJSON(data []byte, options LoaderOptions) (interface{}, error) {
+
+	if options.ForbidDuplicateKeys {
+		if err := checkDuplicateKeys(data); err != nil {
+			return nil, err
+		}
+	}
+
+	var document interface{}
+	err := json.Unmarshal(data, &document)
+	if err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// The JSON text of a document given by a loader : the source of the string
+// loaders, the encoded source of the Go loaders, the encoded document otherwise
+func documentBytes(l JSONLoader, document interface{}) ([]byte, error) {
+	switch loader := l.(type) {
+	case *jsonStringLoader:
+		return []byte(loader.source), nil
+	case *jsonGoLoader:
+		return json.Marshal(loader.source)
+	}
+	return json.Marshal(document)
+}
+
+// Returns an error for the first object of a JSON text having twice the same key
+func checkDuplicateKeys(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	return checkDuplicateKeysOfValue(decoder, NewJSONContext(STRING_CONTEXT_ROOT, nil))
+}
+
+func checkDuplicateKeysOfValue(decoder *json.Decoder, context *JSONContext) error {
+
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token {
+
+	case json.Delim('{'):
+		keys := make(map[string]bool)
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			key := token.(string)
+			if keys[key] {
+				return errors.New(fmt.Sprintf(ERROR_MESSAGE_DUPLICATE_KEY_X_IN_Y, key, context))
+			}
+			keys[key] = true
+			if err := checkDuplicateKeysOfValue(decoder, NewJSONContext(key, context)); err != nil {
+				return err
+			}
+		}
+		_, err = decoder.Token()
+
+	case json.Delim('['):
+		for i := 0; decoder.More(); i++ {
+			if err := checkDuplicateKeysOfValue(decoder, NewJSONContext(strconv.Itoa(i), context)); err != nil {
+				return err
+			}
+		}
+		_, err = decoder.Token()
+	}
+
+	return err
+}
+
+// Decodes a JSON text as json.Unmarshal does, also returning the keys of each
+// object in the order of the text, by the address of the object's map.
+// The last of duplicated keys is kept, at the place of the first.
+func decodeWithKeyOrders(data []byte) (interface{}, map[uintptr][]string, error) {
+	keyOrders := make(map[uintptr][]string)
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	document, err := decodeValueWithKeyOrders(decoder, keyOrders)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		// the same error as json.Unmarshal
+		var document interface{}
+		return nil, nil, json.Unmarshal(data, &document)
+	}
+	return document, keyOrders, nil
+}
+
+func decodeValueWithKeyOrders(decoder *json.Decoder, keyOrders map[uintptr][]string) (interface{}, error) {
+
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch token {
+
+	case json.Delim('{'):
+		object := make(map[string]interface{})
+		var keys []string
+		for decoder.More() {
+			token, err := decoder.Token()
+			if err != nil {
+				return nil, err
+			}
+			key := token.(string)
+			if _, ok := object[key]; !ok {
+				keys = append(keys, key)
+			}
+			if object[key], err = decodeValueWithKeyOrders(decoder, keyOrders); err != nil {
+				return nil, err
+			}
+		}
+		keyOrders[reflect.ValueOf(object).Pointer()] = keys
+		_, err = decoder.Token()
+		return object, err
+
+	case json.Delim('['):
+		array := []interface{}{}
+		for decoder.More() {
+			item, err := decodeValueWithKeyOrders(decoder, keyOrders)
+			if err != nil {
+				return nil, err
+			}
+			array = append(array, item)
+		}
+		_, err = decoder.Token()
+		return array, err
+	}
+
+	return token, nil
+}
+
+// Decodes a JSON text, returning its numbers as they are written, by JSON
+// pointer. The last of duplicated keys is kept, as json.Unmarshal does.
+func decodeNumberTexts(data []byte) (map[string]string, error) {
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var document interface{}
+	if err := decoder.Decode(&document); err != nil {
+		return nil, err
+	}
+
+	numberTexts := make(map[string]string)
+	var walk func(node interface{}, pointer string)
+	walk = func(node interface{}, pointer string) {
+		switch n := node.(type) {
+		case map[string]interface{}:
+			for key, child := range n {
+				walk(child, pointer+"/"+escapeJsonPointerToken(key))
+			}
+		case []interface{}:
+			for i, child := range n {
+				walk(child, pointer+"/"+strconv.Itoa(i))
+			}
+		case json.Number:
+			numberTexts[pointer] = n.String()
+		}
+	}
+	walk(document, "")
+
+	return numberTexts, nil
 }
 
 // JSON Reference loader
 // references are used to load JSONs from files and HTTP
 
 type jsonReferenceLoader struct {
-	source string
+	source  string
+	options LoaderOptions
 }
 
 func (l *jsonReferenceLoader) jsonSource() interface{} {
@@ -60,6 +249,10 @@ func NewReferenceLoader(source string) *jsonReferenceLoader {
 	return &jsonReferenceLoader{source: source}
 }
 
+func NewReferenceLoaderWithOptions(source string, options LoaderOptions) *jsonReferenceLoader {
+	return &jsonReferenceLoader{source: source, options: options}
+}
+
 func (l *jsonReferenceLoader) loadJSON() (interface{}, error) {
 
 	var err error
@@ -95,12 +288,15 @@ func (l *jsonReferenceLoader) loadJSON() (interface{}, error) {
 
 }
 
-func (l *jsonReferenceLoader) loadSchema() (*Schema, error) {
+func (l *jsonReferenceLoader) loadSchema(options SchemaLoaderOptions) (*Schema, error) {
 
 	var err error
 
-	d := Schema{}
+	d := Schema{options: options}
 	d.pool = newSchemaPool()
+	d.pool.refResolver = options.RefResolver
+	d.pool.registry = options.Registry
+	d.pool.httpCache = l.options.HTTPCache
 	d.referencePool = newSchemaReferencePool()
 
 	d.documentReference, err = gojsonreference.NewJsonReference(l.jsonSource().(string))
@@ -124,6 +320,14 @@ func (l *jsonReferenceLoader) loadSchema() (*Schema, error) {
 
 func (l *jsonReferenceLoader) loadFromHTTP(address string) (interface{}, error) {
 
+	if l.options.HTTPCache != nil {
+		bodyBuff, err := l.options.HTTPCache.get(address)
+		if err != nil {
+			return nil, err
+		}
+		return decodeLoadedJSON(bodyBuff, l.options)
+	}
+
 	resp, err := http.Get(address)
 	if err != nil {
 		return nil, err
@@ -139,13 +343,7 @@ func (l *jsonReferenceLoader) loadFromHTTP(address string) (interface{}, error)
 		return nil, err
 	}
 
-	var document interface{}
-	err = json.Unmarshal(bodyBuff, &document)
-	if err != nil {
-		return nil, err
-	}
-
-	return document, nil
+	return decodeLoadedJSON(bodyBuff, l.options)
 }
 
 func (l *jsonReferenceLoader) loadFromFile(path string) (interface{}, error) {
@@ -155,19 +353,14 @@ func (l *jsonReferenceLoader) loadFromFile(path string) (interface{}, error) {
 		return nil, err
 	}
 
-	var document interface{}
-	err = json.Unmarshal(bodyBuff, &document)
-	if err != nil {
-		return nil, err
-	}
-
-	return document, nil
+	return decodeLoadedJSON(bodyBuff, l.options)
 }
 
 // JSON string loader
 
 type jsonStringLoader struct {
-	source string
+	source  string
+	options LoaderOptions
 }
 
 func (l *jsonStringLoader) jsonSource() interface{} {
@@ -178,20 +371,17 @@ func NewStringLoader(source string) *jsonStringLoader {
 	return &jsonStringLoader{source: source}
 }
 
-func (l *jsonStringLoader) loadJSON() (interface{}, error) {
-
-	var document interface{}
+func NewStringLoaderWithOptions(source string, options LoaderOptions) *jsonStringLoader {
+	return &jsonStringLoader{source: source, options: options}
+}
 
-	err := json.Unmarshal([]byte(l.jsonSource().(string)), &document)
-	if err != nil {
-		return nil, err
-	}
+func (l *jsonStringLoader) loadJSON() (interface{}, error) {
 
-	return document, nil
+	return decodeLoadedJSON([]byte(l.jsonSource().(string)), l.options)
 
 }
 
-func (l *jsonStringLoader) loadSchema() (*Schema, error) {
+func (l *jsonStringLoader) loadSchema(options SchemaLoaderOptions) (*Schema, error) {
 
 	var err error
 
@@ -200,8 +390,11 @@ func (l *jsonStringLoader) loadSchema() (*Schema, error) {
 		return nil, err
 	}
 
-	d := Schema{}
+	d := Schema{options: options}
 	d.pool = newSchemaPool()
+	d.pool.refResolver = options.RefResolver
+	d.pool.registry = options.Registry
+	d.pool.httpCache = l.options.HTTPCache
 	d.referencePool = newSchemaReferencePool()
 	d.documentReference, err = gojsonreference.NewJsonReference("#")
 	d.pool.SetStandaloneDocument(document)
@@ -253,7 +446,7 @@ func (l *jsonGoLoader) loadJSON() (interface{}, error) {
 
 }
 
-func (l *jsonGoLoader) loadSchema() (*Schema, error) {
+func (l *jsonGoLoader) loadSchema(options SchemaLoaderOptions) (*Schema, error) {
 
 	var err error
 
@@ -262,8 +455,10 @@ func (l *jsonGoLoader) loadSchema() (*Schema, error) {
 		return nil, err
 	}
 
-	d := Schema{}
+	d := Schema{options: options}
 	d.pool = newSchemaPool()
+	d.pool.refResolver = options.RefResolver
+	d.pool.registry = options.Registry
 	d.referencePool = newSchemaReferencePool()
 	d.documentReference, err = gojsonreference.NewJsonReference("#")
 	d.pool.SetStandaloneDocument(document)
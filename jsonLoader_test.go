@@ -0,0 +1,200 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for the loaders.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestForbidDuplicateKeys(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"properties": {"a": {"type": "integer"}}}`))
+	assert.Nil(t, err)
+
+	forbid := LoaderOptions{ForbidDuplicateKeys: true}
+	document := `{"a": "x", "b": [{"c": 1, "d": {"a": 1}}, {"c": 2, "c": 3}], "a": 1}`
+
+	result, err := schema.Validate(NewStringLoader(document))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	_, err = schema.Validate(NewStringLoaderWithOptions(document, forbid))
+	if assert.NotNil(t, err) {
+		assert.Equal(t, `Duplicate key "c" in #/b/1`, err.Error())
+	}
+
+	result, err = schema.Validate(NewStringLoaderWithOptions(`{"a": 1, "b": {"a": 2}}`, forbid))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	_, err = schema.Validate(NewStringLoaderWithOptions(`{"a": 1,}`, forbid))
+	assert.NotNil(t, err)
+}
+
+// common.json, referenced by the schemas of TestExternalReferences
+const commonSchemaDocument = `{
+	"definitions": {
+		"Address": {
+			"properties": {
+				"street": {"$ref": "#/definitions/Street"},
+				"geo": {"properties": {"zip": {"type": "string", "pattern": "^[0-9]{5}$"}}}
+			},
+			"required": ["street"]
+		},
+		"Street": {"type": "string"}
+	}
+}`
+
+func TestExternalReferences(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "gojsonschema")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(commonSchemaDocument))
+	}))
+	defer server.Close()
+
+	mainSchema := `{"properties": {
+		"home": {"$ref": "%s#/definitions/Address"},
+		"zip": {"$ref": "%s#/definitions/Address/properties/geo/properties/zip"}
+	}}`
+
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "common.json"), []byte(commonSchemaDocument), 0644))
+	assert.Nil(t, ioutil.WriteFile(filepath.Join(dir, "main.json"), []byte(strings.Replace(mainSchema, "%s", "common.json", -1)), 0644))
+
+	commonFile := "file://" + filepath.Join(dir, "common.json")
+	loaders := map[string]JSONLoader{
+		"local file":    NewReferenceLoader("file://" + filepath.Join(dir, "main.json")),
+		"absolute file": NewStringLoader(strings.Replace(mainSchema, "%s", commonFile, -1)),
+		"remote":        NewStringLoader(strings.Replace(mainSchema, "%s", server.URL+"/common.json", -1)),
+	}
+
+	for name, loader := range loaders {
+		schema, err := NewSchema(loader)
+		if !assert.Nil(t, err, name) {
+			continue
+		}
+
+		home := schema.rootSchema.propertyChild("home")
+		assert.True(t, strings.HasSuffix(home.refSchema.location, "common.json#/definitions/Address"), name)
+
+		result, err := schema.Validate(NewStringLoader(`{"home": {"street": "Main"}, "zip": "12345"}`))
+		assert.Nil(t, err, name)
+		assert.True(t, result.Valid(), name)
+
+		result, err = schema.Validate(NewStringLoader(`{"home": {"street": 1}, "zip": "ABC"}`))
+		assert.Nil(t, err, name)
+		var contexts []string
+		for _, resultError := range result.Errors() {
+			contexts = append(contexts, resultError.Context.String())
+		}
+		assert.ElementsMatch(t, []string{"#/home/street", "#/zip"}, contexts, name)
+	}
+}
+
+func TestReferences(t *testing.T) {
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(commonSchemaDocument))
+	}))
+	defer server.Close()
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"home": {"$ref": "` + server.URL + `/common.json#/definitions/Address"},
+			"work": {"$ref": "` + server.URL + `/common.json#/definitions/Address"},
+			"parent": {"$ref": "#"},
+			"kind": {"oneOf": [{"$ref": "#/definitions/kind"}]}
+		},
+		"definitions": {"kind": {"enum": ["a", "b"]}}
+	}`))
+	assert.Nil(t, err)
+
+	assert.Equal(t, []string{
+		"#",
+		"#/definitions/kind",
+		server.URL + "/common.json#/definitions/Address",
+		server.URL + "/common.json#/definitions/Street",
+	}, schema.References())
+}
+
+func TestRefResolver(t *testing.T) {
+
+	documents := map[string]interface{}{
+		"urn:example:address": map[string]interface{}{
+			"type":       "object",
+			"properties": map[string]interface{}{"zip": map[string]interface{}{"$ref": "schema://internal/defs.json#/definitions/zip"}},
+		},
+		"schema://internal/defs.json": map[string]interface{}{
+			"definitions": map[string]interface{}{"zip": map[string]interface{}{"type": "string", "pattern": "^[0-9]{5}$"}},
+		},
+	}
+	resolved := make(map[string]int)
+	resolver := func(uri string) (interface{}, error) {
+		resolved[uri]++
+		return documents[uri], nil
+	}
+
+	schema, err := NewSchemaWithOptions(NewStringLoader(`{
+		"properties": {
+			"home": {"$ref": "urn:example:address"},
+			"work": {"$ref": "urn:example:address"},
+			"zip": {"$ref": "schema://internal/defs.json#/definitions/zip"},
+			"self": {"$ref": "#/definitions/self"}
+		},
+		"definitions": {"self": {"type": "boolean"}}
+	}`), SchemaLoaderOptions{RefResolver: resolver})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]int{"urn:example:address": 1, "schema://internal/defs.json": 1}, resolved)
+
+	result, err := schema.Validate(NewStringLoader(`{"home": {"zip": "75001"}, "work": {"zip": "750"}, "zip": "12345", "self": true}`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, "#/work/zip", result.Errors()[0].Context.String())
+	}
+
+	// the references left to the loaders must still be canonical
+	_, err = NewSchemaWithOptions(NewStringLoader(`{"$ref": "urn:example:unknown"}`), SchemaLoaderOptions{RefResolver: resolver})
+	assert.EqualError(t, err, "Reference urn:example:unknown must be canonical")
+
+	_, err = NewSchemaWithOptions(NewStringLoader(`{"$ref": "urn:example:failing#/definitions/a"}`), SchemaLoaderOptions{
+		RefResolver: func(uri string) (interface{}, error) {
+			return nil, errors.New("not in the registry")
+		},
+	})
+	assert.EqualError(t, err, "Reference urn:example:failing cannot be resolved : not in the registry")
+}
@@ -0,0 +1,120 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      Kinds of the validation errors, for errors.Is.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+// KeywordError is the kind of the ResultErrors reported by a keyword.
+// errors.Is tells whether an error, a ResultError or the ResultErrors of
+// Result.Err, is of this kind :
+//
+//	if errors.Is(result.Err(), gojsonschema.ErrRequired) { ... }
+//
+// Any keyword, including custom ones, can be matched with a KeywordError of
+// its own, ex &KeywordError{Keyword: "divisibleByAny"}.
+type KeywordError struct {
+	Keyword string
+}
+
+func (e *KeywordError) Error() string {
+	return e.Keyword
+}
+
+// Kinds of the errors of the standard keywords and of the extensions
+var (
+	ErrType                 = &KeywordError{KEY_TYPE}
+	ErrEnum                 = &KeywordError{KEY_ENUM}
+	ErrMultipleOf           = &KeywordError{KEY_MULTIPLE_OF}
+	ErrMinimum              = &KeywordError{KEY_MINIMUM}
+	ErrMaximum              = &KeywordError{KEY_MAXIMUM}
+	ErrExclusiveMinimum     = &KeywordError{KEY_EXCLUSIVE_MINIMUM}
+	ErrExclusiveMaximum     = &KeywordError{KEY_EXCLUSIVE_MAXIMUM}
+	ErrMinLength            = &KeywordError{KEY_MIN_LENGTH}
+	ErrMaxLength            = &KeywordError{KEY_MAX_LENGTH}
+	ErrPattern              = &KeywordError{KEY_PATTERN}
+	ErrFormat               = &KeywordError{KEY_FORMAT}
+	ErrContentEncoding      = &KeywordError{KEY_CONTENT_ENCODING}
+	ErrContentMediaType     = &KeywordError{KEY_CONTENT_MEDIA_TYPE}
+	ErrMinProperties        = &KeywordError{KEY_MIN_PROPERTIES}
+	ErrMaxProperties        = &KeywordError{KEY_MAX_PROPERTIES}
+	ErrRequired             = &KeywordError{KEY_REQUIRED}
+	ErrDependencies         = &KeywordError{KEY_DEPENDENCIES}
+	ErrAdditionalProperties = &KeywordError{KEY_ADDITIONAL_PROPERTIES}
+	ErrItems                = &KeywordError{KEY_ITEMS}
+	ErrMinItems             = &KeywordError{KEY_MIN_ITEMS}
+	ErrMaxItems             = &KeywordError{KEY_MAX_ITEMS}
+	ErrUniqueItems          = &KeywordError{KEY_UNIQUE_ITEMS}
+	ErrAdditionalItems      = &KeywordError{KEY_ADDITIONAL_ITEMS}
+	ErrOneOf                = &KeywordError{KEY_ONE_OF}
+	ErrAnyOf                = &KeywordError{KEY_ANY_OF}
+	ErrAllOf                = &KeywordError{KEY_ALL_OF}
+	ErrNot                  = &KeywordError{KEY_NOT}
+	ErrSorted               = &KeywordError{KEY_X_SORTED}
+	ErrPropertyOrder        = &KeywordError{KEY_X_PROPERTY_ORDER}
+	ErrNumberFormat         = &KeywordError{KEY_X_FORMAT}
+	ErrAnyFormat            = &KeywordError{KEY_X_ANY_FORMAT}
+	ErrMaxDecimals          = &KeywordError{KEY_X_MAX_DECIMALS}
+)
+
+// The kinds above, by keyword
+var keywordErrors = make(map[string]*KeywordError)
+
+func init() {
+	for _, e := range []*KeywordError{
+		ErrType, ErrEnum, ErrMultipleOf, ErrMinimum, ErrMaximum, ErrExclusiveMinimum, ErrExclusiveMaximum,
+		ErrMinLength, ErrMaxLength, ErrPattern, ErrFormat, ErrContentEncoding, ErrContentMediaType,
+		ErrMinProperties, ErrMaxProperties, ErrRequired, ErrDependencies, ErrAdditionalProperties,
+		ErrItems, ErrMinItems, ErrMaxItems, ErrUniqueItems, ErrAdditionalItems,
+		ErrOneOf, ErrAnyOf, ErrAllOf, ErrNot, ErrSorted, ErrPropertyOrder, ErrNumberFormat, ErrAnyFormat, ErrMaxDecimals,
+	} {
+		keywordErrors[e.Keyword] = e
+	}
+}
+
+func (v ResultError) Error() string {
+	return v.String()
+}
+
+// Is tells whether the error was reported by the keyword of a KeywordError
+func (v ResultError) Is(target error) bool {
+	kind, ok := target.(*KeywordError)
+	return ok && kind.Keyword == v.Reason
+}
+
+// Unwrap returns the kind of the error, nil for custom keywords
+func (v ResultError) Unwrap() error {
+	if kind, ok := keywordErrors[v.Reason]; ok {
+		return kind
+	}
+	return nil
+}
+
+// Unwrap returns the errors, so that errors.Is and errors.As look into them
+func (rerrs ResultErrors) Unwrap() []error {
+	unwrapped := make([]error, len(rerrs))
+	for i, rerr := range rerrs {
+		unwrapped[i] = rerr
+	}
+	return unwrapped
+}
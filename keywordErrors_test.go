@@ -0,0 +1,69 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for the kinds of the validation errors.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestKeywordErrors(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"required": ["a"], "properties": {"b": {"type": "string"}}}`))
+	assert.Nil(t, err)
+
+	result, err := schema.Validate(NewStringLoader(`{"b": 1}`))
+	assert.Nil(t, err)
+
+	err = fmt.Errorf("invalid request: %w", result.Err())
+	assert.True(t, errors.Is(err, ErrRequired))
+	assert.True(t, errors.Is(err, ErrType))
+	assert.False(t, errors.Is(err, ErrMinimum))
+
+	var rerr ResultError
+	if assert.True(t, errors.As(err, &rerr)) {
+		assert.Equal(t, result.Errors()[0], rerr)
+	}
+
+	for _, rerr := range result.Errors() {
+		assert.Equal(t, rerr.String(), rerr.Error())
+		kind := ErrRequired
+		if rerr.Reason == KEY_TYPE {
+			kind = ErrType
+		}
+		assert.True(t, errors.Is(rerr, kind))
+		assert.Equal(t, kind, errors.Unwrap(rerr))
+	}
+
+	// custom keywords
+	rerr = ResultError{Reason: "divisibleByAny"}
+	assert.True(t, errors.Is(rerr, &KeywordError{Keyword: "divisibleByAny"}))
+	assert.False(t, errors.Is(rerr, ErrType))
+	assert.Nil(t, errors.Unwrap(rerr))
+}
@@ -0,0 +1,83 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      Custom keywords.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"errors"
+	"fmt"
+)
+
+// KeywordValidator implements a custom keyword, see RegisterKeyword and
+// SchemaLoaderOptions.Keywords.
+type KeywordValidator interface {
+
+	// Validates a node of the document whose subSchema declares the keyword.
+	// parent is the object or array holding the node, nil at the root of the
+	// document, so that the node can be checked against its siblings.
+	// context is the path of the node, nil with ValidateOptions.IsValid.
+	// A non nil error fails the node, its message becoming the Requirement of
+	// the ResultError whose Reason is the keyword.
+	Validate(value interface{}, parent interface{}, context *JSONContext) error
+}
+
+// KeywordCompiler is implemented by the KeywordValidators that depend on the
+// value of their keyword. Compile is given the value of the keyword of each
+// subSchema declaring it, as decoded from the schema, and the KeywordValidator
+// it returns validates the nodes of this subSchema. An error makes the schema
+// invalid.
+type KeywordCompiler interface {
+	Compile(value interface{}) (KeywordValidator, error)
+}
+
+// The keywords added by RegisterKeyword
+var keywordValidators = make(map[string]KeywordValidator)
+
+// RegisterKeyword adds a custom keyword to the schemas loaded afterwards, as
+// SchemaLoaderOptions.Keywords does for a single schema. Register the keywords
+// before loading schemas, the registry is not safe for concurrent modification.
+func RegisterKeyword(name string, v KeywordValidator) {
+	keywordValidators[name] = v
+}
+
+// Declares a custom keyword on the subSchema, compiling its validator
+func (s *subSchema) setKeyword(keyword string, value interface{}, validator KeywordValidator) error {
+
+	if compiler, ok := validator.(KeywordCompiler); ok {
+		var err error
+		if validator, err = compiler.Compile(value); err != nil {
+			return errors.New(fmt.Sprintf(ERROR_MESSAGE_INVALID_KEYWORD_X, keyword, err.Error()))
+		}
+	}
+
+	if s.keywords == nil {
+		s.keywords = make(map[string]KeywordValidator)
+		s.keywordValues = make(map[string]interface{})
+	}
+	s.keywords[keyword] = validator
+	s.keywordValues[keyword] = value
+
+	return nil
+}
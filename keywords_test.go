@@ -0,0 +1,197 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for the custom keywords.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Checks that a date comes after the "start" property of the same object
+type afterStartKeyword struct{}
+
+func (afterStartKeyword) Validate(value interface{}, parent interface{}, context *JSONContext) error {
+	object, _ := parent.(map[string]interface{})
+	start, _ := object["start"].(string)
+	if date, ok := value.(string); ok && date <= start {
+		return errors.New("must be after start")
+	}
+	return nil
+}
+
+// Records the nodes it is given
+type recordingKeyword struct {
+	parents  []interface{}
+	contexts []string
+}
+
+func (k *recordingKeyword) Validate(value interface{}, parent interface{}, context *JSONContext) error {
+	k.parents = append(k.parents, parent)
+	k.contexts = append(k.contexts, context.String())
+	return nil
+}
+
+func TestKeywordValidatorSiblings(t *testing.T) {
+
+	options := SchemaLoaderOptions{Keywords: map[string]KeywordValidator{"afterStart": afterStartKeyword{}}}
+	schema, err := NewSchemaWithOptions(NewStringLoader(`{
+		"type": "array",
+		"items": {
+			"properties": {
+				"start": {"type": "string"},
+				"end": {"type": "string", "afterStart": true}
+			}
+		}
+	}`), options)
+	assert.Nil(t, err)
+
+	result, err := schema.Validate(NewStringLoader(`[
+		{"start": "2026-01-01", "end": "2026-02-01"},
+		{"start": "2026-03-01", "end": "2026-02-01"},
+		{"start": "2026-03-01", "end": 3}
+	]`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 2) {
+		assert.Equal(t, "#/1/end", result.Errors()[0].Context.String())
+		assert.Equal(t, "afterStart", result.Errors()[0].Reason)
+		assert.Equal(t, "must be after start", result.Errors()[0].Requirement)
+		assert.Equal(t, "#/2/end", result.Errors()[1].Context.String())
+		assert.Equal(t, KEY_TYPE, result.Errors()[1].Reason)
+	}
+
+	// the options are not kept by the compiled schemas
+	data, err := schema.MarshalCompiled()
+	assert.Nil(t, err)
+	_, err = LoadCompiled(data)
+	assert.EqualError(t, err, "Keyword afterStart is not registered")
+
+	// unknown keywords are ignored without the option
+	schema, err = NewSchema(NewStringLoader(`{"afterStart": true}`))
+	assert.Nil(t, err)
+	result, err = schema.Validate(NewStringLoader(`"2026-01-01"`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+}
+
+func TestKeywordValidatorParent(t *testing.T) {
+
+	keyword := &recordingKeyword{}
+	options := SchemaLoaderOptions{Keywords: map[string]KeywordValidator{"record": keyword}}
+	schema, err := NewSchemaWithOptions(NewStringLoader(`{
+		"record": true,
+		"properties": {"a": {"items": {"anyOf": [{"record": 1}]}}},
+		"additionalProperties": {"record": 2}
+	}`), options)
+	assert.Nil(t, err)
+
+	result, err := schema.Validate(NewStringLoader(`{"a": ["x"], "b": 1}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	assert.Equal(t, []string{"#/b", "#/a/0", "#"}, keyword.contexts)
+	assert.Equal(t, []interface{}{map[string]interface{}{"a": []interface{}{"x"}, "b": float64(1)}, []interface{}{"x"}, nil}, keyword.parents)
+}
+
+// {"divisibleByAny": [3, 5]}
+type divisibleByAnyKeyword []float64
+
+func (d divisibleByAnyKeyword) Compile(value interface{}) (KeywordValidator, error) {
+	var divisors divisibleByAnyKeyword
+	list, _ := value.([]interface{})
+	for _, v := range list {
+		divisor, ok := v.(float64)
+		if !ok || divisor <= 0 {
+			return nil, errors.New("divisibleByAny must be an array of positive numbers")
+		}
+		divisors = append(divisors, divisor)
+	}
+	return divisors, nil
+}
+
+func (d divisibleByAnyKeyword) Validate(value interface{}, parent interface{}, context *JSONContext) error {
+	number, ok := value.(float64)
+	if !ok {
+		return nil
+	}
+	for _, divisor := range d {
+		if math.Mod(number, divisor) == 0 {
+			return nil
+		}
+	}
+	return fmt.Errorf("must be divisible by one of %v", []float64(d))
+}
+
+func TestRegisterKeyword(t *testing.T) {
+
+	RegisterKeyword("divisibleByAny", divisibleByAnyKeyword(nil))
+	defer delete(keywordValidators, "divisibleByAny")
+
+	schema, err := NewSchema(NewStringLoader(`{"items": {"divisibleByAny": [3, 5]}}`))
+	assert.Nil(t, err)
+
+	result, err := schema.Validate(NewStringLoader(`[3, 10, 15, "x", 7]`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, "#/4", result.Errors()[0].Context.String())
+		assert.Equal(t, "divisibleByAny", result.Errors()[0].Reason)
+		assert.Equal(t, "must be divisible by one of [3 5]", result.Errors()[0].Requirement)
+	}
+
+	// the raw value is kept with the subSchema
+	assert.Equal(t, map[string]interface{}{"divisibleByAny": []interface{}{float64(3), float64(5)}}, marshalSubSchema(schema.rootSchema.itemsChildren[0]))
+
+	// and compiled again when loading a compiled schema
+	data, err := schema.MarshalCompiled()
+	assert.Nil(t, err)
+	loaded, err := LoadCompiled(data)
+	assert.Nil(t, err)
+	result, err = loaded.Validate(NewStringLoader(`[9, 7]`))
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 1)
+
+	_, err = NewSchema(NewStringLoader(`{"divisibleByAny": [3, "5"]}`))
+	assert.EqualError(t, err, "Invalid keyword divisibleByAny : divisibleByAny must be an array of positive numbers")
+
+	// the first invalid keyword by name
+	RegisterKeyword("anotherDivisibleByAny", divisibleByAnyKeyword(nil))
+	defer delete(keywordValidators, "anotherDivisibleByAny")
+	for i := 0; i < 10; i++ {
+		_, err = NewSchema(NewStringLoader(`{"divisibleByAny": [3, "5"], "anotherDivisibleByAny": [-1]}`))
+		assert.EqualError(t, err, "Invalid keyword anotherDivisibleByAny : divisibleByAny must be an array of positive numbers")
+	}
+
+	// the options take precedence over the registry
+	options := SchemaLoaderOptions{Keywords: map[string]KeywordValidator{"divisibleByAny": afterStartKeyword{}}}
+	schema, err = NewSchemaWithOptions(NewStringLoader(`{"divisibleByAny": "not compiled"}`), options)
+	assert.Nil(t, err)
+	result, err = schema.Validate(NewStringLoader(`7`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+}
@@ -29,11 +29,25 @@ const (
 	STRING_NUMBER                     = "number"
 	STRING_ARRAY_OF_STRINGS           = "array of strings"
 	STRING_ARRAY_OF_SCHEMAS           = "array of schemas"
+	STRING_OBJECT_OF_STRINGS          = "object of strings"
+	STRING_STRING_OR_OBJECT           = "string or object of strings"
 	STRING_SCHEMA                     = "schema"
 	STRING_SCHEMA_OR_ARRAY_OF_STRINGS = "schema or array of strings"
 	STRING_PROPERTIES                 = "properties"
 	STRING_DEPENDENCY                 = "dependency"
 	STRING_PROPERTY                   = "property"
+	STRING_VALUE                      = "value"
+	STRING_REQUIREMENT                = "requirement"
+	STRING_EXPECTED                   = "expected"
+	STRING_ACTUAL                     = "actual"
+	STRING_SUGGESTION                 = "suggestion"
+	STRING_DUPLICATES                 = "duplicates"
+	STRING_AFTER                      = "after"
+	STRING_PATTERN_FLAGS              = "string of regex flags among " + PATTERN_FLAGS
+	STRING_FINITE_NUMBER              = "finite number"
+	STRING_NOT_NULL                   = "not null"
+	STRING_SORT_ORDER                 = `"` + SORTED_ASC + `" or "` + SORTED_DESC + `"`
+	STRING_NUMBER_FORMAT              = `"` + NUMBER_FORMAT_INT32 + `" or "` + NUMBER_FORMAT_INT64 + `"`
 
 	STRING_CONTEXT_ROOT         = "#"
 	STRING_ROOT_SCHEMA_PROPERTY = "#"
@@ -57,5 +71,27 @@ const (
 	ERROR_MESSAGE_X_CANNOT_BE_GREATER_THAN_Y        = `%s cannot be greater than %s`
 	ERROR_MESSAGE_X_MUST_BE_STRICTLY_GREATER_THAN_0 = `%s must be strictly greater than 0`
 	ERROR_MESSAGE_X_CANNOT_BE_USED_WITHOUT_Y        = `%s cannot be used without %s`
+	ERROR_MESSAGE_X_CANNOT_BE_USED_WITH_Y           = `%s cannot be used with %s`
 	ERROR_MESSAGE_REFERENCE_X_MUST_BE_CANONICAL     = `Reference %s must be canonical`
+	ERROR_MESSAGE_REFERENCE_X_IS_NOT_IN_Y           = `Reference %s is not the $ref of one of the %s subSchemas`
+	ERROR_MESSAGE_REFERENCE_X_CANNOT_BE_RESOLVED    = `Reference %s cannot be resolved : %s`
+	ERROR_MESSAGE_COMPILED_SCHEMA_VERSION           = `Unsupported compiled schema version %d`
+	ERROR_MESSAGE_DUPLICATE_KEY_X_IN_Y              = `Duplicate key "%s" in %s`
+	ERROR_MESSAGE_SCHEMA_LOAD_X                     = `Could not load schema %s : %s`
+	ERROR_MESSAGE_INVALID_KEYWORD_X                 = `Invalid keyword %s : %s`
+	ERROR_MESSAGE_UNKNOWN_KEYWORD_X                 = `Unknown keyword %s`
+	ERROR_MESSAGE_KEYWORD_X_IS_NOT_REGISTERED       = `Keyword %s is not registered`
+	ERROR_MESSAGE_NO_SUBSCHEMA_AT_X                 = `No subSchema at %s`
+	ERROR_MESSAGE_NO_OVERRIDE_FOR_X                 = `No override for %s`
+	ERROR_MESSAGE_X_IS_EMPTY_AT_Y                   = `%s is empty at %s`
+	ERROR_MESSAGE_INVALID_PATCH_OPERATION_X         = `Invalid JSON Patch operation "%s"`
+	ERROR_MESSAGE_INVALID_PATCH_PATH_X              = `Invalid JSON Patch path "%s"`
+	ERROR_MESSAGE_PATCH_TEST_FAILED_AT_X            = `JSON Patch test failed at "%s"`
+	ERROR_MESSAGE_X_AT_Y_CANNOT_BE_APPLIED          = `%s at %s cannot be applied : %s`
+	ERROR_MESSAGE_REFERENCE_X_IS_CIRCULAR           = `Reference %s is circular`
+	ERROR_MESSAGE_DEADLINE_EXCEEDED                 = `Validation deadline exceeded`
+	ERROR_MESSAGE_EMPTY_DOCUMENT                    = `Document is empty`
+	ERROR_MESSAGE_INVALID_MAP_KEY_X_OF_TYPE_Y       = `Map key %v of type %s cannot be a property name`
+	ERROR_MESSAGE_SCHEMA_HAS_NO_ID                  = `Schema has no $id`
+	ERROR_MESSAGE_SCHEMA_X_HAS_NO_DOCUMENT          = `Schema %s has no JSON document`
 )
@@ -0,0 +1,172 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      Composition of schemas by the $merge and $patch extensions.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/xeipuuv/gojsonreference"
+)
+
+// Returns the document of a subSchema holding $merge or $patch : a copy of
+// its source, given inline or by a $ref resolved against base, to which the
+// JSON Merge Patch ( RFC 7386 ) or the JSON Patch ( RFC 6902 ) is applied.
+// references holds the sources already followed, to stop on circular ones.
+func (d *Schema) composeSchema(m map[string]interface{}, base gojsonreference.JsonReference, references map[string]bool) (map[string]interface{}, error) {
+
+	if existsMapKey(m, KEY_MERGE) && existsMapKey(m, KEY_PATCH) {
+		return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_X_CANNOT_BE_USED_WITH_Y, KEY_MERGE, KEY_PATCH))
+	}
+	keyword := KEY_MERGE
+	if existsMapKey(m, KEY_PATCH) {
+		keyword = KEY_PATCH
+	}
+
+	composition, ok := m[keyword].(map[string]interface{})
+	if !ok {
+		return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, keyword, TYPE_OBJECT))
+	}
+	source, ok := composition[KEY_SOURCE].(map[string]interface{})
+	if !ok {
+		return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, keyword+" "+KEY_SOURCE, TYPE_OBJECT))
+	}
+	source, err := d.compositionSource(source, base, references)
+	if err != nil {
+		return nil, err
+	}
+
+	var composed interface{} = copyJSON(source)
+	switch keyword {
+	case KEY_MERGE:
+		with, ok := composition[KEY_WITH].(map[string]interface{})
+		if !ok {
+			return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, keyword+" "+KEY_WITH, TYPE_OBJECT))
+		}
+		composed = mergePatch(composed, with)
+	case KEY_PATCH:
+		with, ok := composition[KEY_WITH].([]interface{})
+		if !ok {
+			return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_AN_Y, keyword+" "+KEY_WITH, TYPE_ARRAY))
+		}
+		patch, err := decodePatch(with)
+		if err != nil {
+			return nil, err
+		}
+		if composed, err = applyPatch(composed, patch); err != nil {
+			return nil, err
+		}
+	}
+
+	composedSchema, ok := composed.(map[string]interface{})
+	if !ok {
+		return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, STRING_SCHEMA, TYPE_OBJECT))
+	}
+	return composedSchema, nil
+}
+
+// Returns the document of the source of a $merge or $patch, following its
+// $ref and composing it when it is itself a $merge or a $patch
+func (d *Schema) compositionSource(source map[string]interface{}, base gojsonreference.JsonReference, references map[string]bool) (map[string]interface{}, error) {
+
+	if ref, ok := source[KEY_REF].(string); ok {
+		jsonReference, err := gojsonreference.NewJsonReference(ref)
+		if err != nil {
+			return nil, err
+		}
+		reference := &jsonReference
+		if !jsonReference.HasFullUrl {
+			if reference, err = base.Inherits(jsonReference); err != nil {
+				return nil, err
+			}
+		}
+		if references[reference.String()] {
+			return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_REFERENCE_X_IS_CIRCULAR, reference.String()))
+		}
+		references[reference.String()] = true
+
+		node, err := d.referencedNode(*reference)
+		if err != nil {
+			return nil, err
+		}
+		if source, ok = node.(map[string]interface{}); !ok {
+			return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, STRING_SCHEMA, TYPE_OBJECT))
+		}
+		base = *reference
+	}
+
+	if existsMapKey(source, KEY_MERGE) || existsMapKey(source, KEY_PATCH) {
+		return d.composeSchema(source, base, references)
+	}
+	return source, nil
+}
+
+// Applies a JSON Merge Patch to a decoded JSON document, modified in place :
+// the members of the patch replace those of the document, recursively for
+// the objects, and its null members remove them
+func mergePatch(document interface{}, patch interface{}) interface{} {
+
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return copyJSON(patch)
+	}
+	documentObject, ok := document.(map[string]interface{})
+	if !ok {
+		documentObject = make(map[string]interface{}, len(patchObject))
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(documentObject, key)
+		} else {
+			documentObject[key] = mergePatch(documentObject[key], value)
+		}
+	}
+	return documentObject
+}
+
+// Decodes the operations of a JSON Patch given as JSON
+func decodePatch(operations []interface{}) ([]PatchOp, error) {
+
+	patch := make([]PatchOp, 0, len(operations))
+	for _, operation := range operations {
+		m, ok := operation.(map[string]interface{})
+		if !ok {
+			return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_X_ITEMS_MUST_BE_TYPE_Y, KEY_PATCH+" "+KEY_WITH, TYPE_OBJECT))
+		}
+		op, _ := m["op"].(string)
+		path, ok := m["path"].(string)
+		if !ok {
+			return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_INVALID_PATCH_PATH_X, fmt.Sprint(m["path"])))
+		}
+		from, ok := m["from"].(string)
+		if !ok && existsMapKey(m, "from") {
+			return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_INVALID_PATCH_PATH_X, fmt.Sprint(m["from"])))
+		}
+		patch = append(patch, PatchOp{Op: op, Path: path, From: from, Value: m["value"]})
+	}
+
+	return patch, nil
+}
@@ -0,0 +1,126 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for the $merge and $patch extensions.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMergeExtension(t *testing.T) {
+
+	extensions := SchemaLoaderOptions{EnableExtensions: true}
+
+	schema, err := NewSchemaWithOptions(NewStringLoader(`{
+		"definitions": {
+			"person": {
+				"type": "object",
+				"properties": {"name": {"type": "string"}, "age": {"type": "integer"}},
+				"required": ["name"],
+				"additionalProperties": false
+			}
+		},
+		"properties": {
+			"person": {"$ref": "#/definitions/person"},
+			"employee": {"$merge": {
+				"source": {"$ref": "#/definitions/person"},
+				"with": {"properties": {"id": {"type": "integer"}, "age": null}, "required": ["id"]}
+			}},
+			"inline": {"$merge": {"source": {"type": "string"}, "with": {"maxLength": 2}}}
+		}
+	}`), extensions)
+	assert.Nil(t, err)
+
+	for document, valid := range map[string]bool{
+		`{"employee": {"name": "a", "id": 1}}`:            true,
+		`{"employee": {"name": "a"}}`:                     false,
+		`{"employee": {"name": "a", "id": 1, "age": 30}}`: false,
+		`{"person": {"name": "a", "age": 30}}`:            true,
+		`{"person": {"name": "a", "id": 1}}`:              false,
+		`{"inline": "ab"}`:                                true,
+		`{"inline": "abc"}`:                               false,
+	} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	// ignored without the extensions, as any unknown keyword
+	schema, err = NewSchema(NewStringLoader(`{"$merge": {"source": {"type": "string"}, "with": {"maxLength": 2}}}`))
+	assert.Nil(t, err)
+	result, err := schema.Validate(NewStringLoader(`1`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+}
+
+func TestPatchExtension(t *testing.T) {
+
+	extensions := SchemaLoaderOptions{EnableExtensions: true}
+
+	schema, err := NewSchemaWithOptions(NewStringLoader(`{
+		"definitions": {
+			"base": {"type": "object", "properties": {"a": {"type": "string"}}, "required": ["a"]},
+			"strict": {"$merge": {"source": {"$ref": "#/definitions/base"}, "with": {"additionalProperties": false}}}
+		},
+		"$patch": {
+			"source": {"$ref": "#/definitions/strict"},
+			"with": [
+				{"op": "test", "path": "/required/0", "value": "a"},
+				{"op": "add", "path": "/properties/b", "value": {"type": "integer"}},
+				{"op": "add", "path": "/required/-", "value": "b"},
+				{"op": "move", "from": "/properties/a", "path": "/properties/c"},
+				{"op": "replace", "path": "/required/0", "value": "c"}
+			]
+		}
+	}`), extensions)
+	assert.Nil(t, err)
+
+	for document, valid := range map[string]bool{
+		`{"b": 1, "c": "x"}`:         true,
+		`{"b": 1}`:                   false,
+		`{"b": 1, "c": "x", "a": 1}`: false,
+		`{"b": "x", "c": "x"}`:       false,
+	} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	for schemaDocument, message := range map[string]string{
+		`{"properties": {"a": {"$patch": {"source": {"type": "string"}, "with": [{"op": "remove", "path": "/maxLength"}]}}}}`: `$patch at #/properties/a cannot be applied : Invalid JSON Patch path "/maxLength"`,
+		`{"$patch": {"source": {"type": "string"}, "with": [{"op": "test", "path": "/type", "value": "integer"}]}}`:           `$patch at # cannot be applied : JSON Patch test failed at "/type"`,
+		`{"$patch": {"source": {"type": "string"}, "with": {"op": "add"}}}`:                                                   `$patch at # cannot be applied : $patch with must be of an array`,
+		`{"$merge": {"source": {"type": "string"}, "with": "a"}}`:                                                             `$merge at # cannot be applied : $merge with must be of type object`,
+		`{"$merge": {"source": "a", "with": {}}}`:                                                                             `$merge at # cannot be applied : $merge source must be of type object`,
+		`{"$merge": {"source": {}, "with": {}}, "$patch": {}}`:                                                                `$merge at # cannot be applied : $merge cannot be used with $patch`,
+		`{"$patch": {"source": {}, "with": [{"op": "replace", "path": "", "value": 1}]}}`:                                     `$patch at # cannot be applied : schema must be of type object`,
+		`{"definitions": {"a": {"$merge": {"source": {"$ref": "#/definitions/a"}, "with": {}}}}, "$ref": "#/definitions/a"}`:  `$merge at #/definitions/a cannot be applied : Reference #/definitions/a is circular`,
+	} {
+		_, err := NewSchemaWithOptions(NewStringLoader(schemaDocument), extensions)
+		assert.EqualError(t, err, message, schemaDocument)
+	}
+}
@@ -0,0 +1,64 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for the validation of the schemas against the meta-schema.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateSchemaDocument(t *testing.T) {
+
+	result, err := ValidateSchemaDocument(NewStringLoader(`{
+		"type": "object",
+		"properties": {"a": {"type": ["string", "null"], "minLength": 1}, "b": {"items": [{"enum": [1, 2]}]}},
+		"required": ["a"],
+		"definitions": {"c": {"$ref": "#/properties/a"}}
+	}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	// the meta-schema itself is a valid schema
+	result, err = ValidateSchemaDocument(NewStringLoader(draft04MetaSchema))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	for schema, context := range map[string]string{
+		`{"properties": {"a": {"type": "text"}}}`: "#/properties/a/type",
+		`{"items": [{"minLength": -1}]}`:          "#/items/0/minLength",
+		`{"required": []}`:                        "#/required",
+		`{"enum": [1, 1]}`:                        "#/enum",
+		`{"exclusiveMinimum": true}`:              "#/exclusiveMinimum",
+		`[]`:                                      "#",
+	} {
+		result, err := ValidateSchemaDocument(NewStringLoader(schema))
+		assert.Nil(t, err)
+		if assert.False(t, result.Valid(), schema) {
+			assert.Equal(t, context, result.Errors()[0].Context.String(), schema)
+		}
+	}
+}
@@ -0,0 +1,153 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      Produces a normalized copy of a validated document.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// ValidateNormalize validates the document and returns a normalized copy of it
+// along with the result.
+//
+// In the normalized document, numbers are json.Number and the properties
+// missing from an object are set to the "default" of their subSchema when
+// there is one. Defaults are looked up through "properties", "items",
+// "additionalItems" and "$ref", never inside anyOf, oneOf, allOf or not since
+// which of their subSchemas applies is ambiguous. The defaults are completed
+// with the defaults of their own properties, except the default being set,
+// so that recursive subSchemas end. Objects are map[string]interface{},
+// whose keys are sorted when marshaled back to JSON.
+//
+// The validation runs against the document as given, defaults excluded.
+func (v *Schema) ValidateNormalize(l JSONLoader) (normalized interface{}, res *Result, err error) {
+
+	var options ValidateOptions
+	root, err := loadDocument(l, &options, v)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res = v.validateDocument(root, options)
+
+	normalized, err = normalizeDocument(root)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = applyDefaults(v.rootSchema, normalized, make(map[*subSchema]bool))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return normalized, res, nil
+}
+
+// Deep copies a document, numbers being decoded as json.Number
+func normalizeDocument(document interface{}) (interface{}, error) {
+
+	documentBytes, err := json.Marshal(document)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeJSONUseNumber(documentBytes)
+}
+
+func decodeJSONUseNumber(data []byte) (interface{}, error) {
+
+	var document interface{}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	err := decoder.Decode(&document)
+	if err != nil {
+		return nil, err
+	}
+
+	return document, nil
+}
+
+// Sets the default values of the missing properties, recursively. The
+// subSchemas whose default is being set are injecting, so that a recursive
+// subSchema does not set its default again inside its own default.
+func applyDefaults(currentSubSchema *subSchema, node interface{}, injecting map[*subSchema]bool) error {
+
+	if currentSubSchema = currentSubSchema.resolvedRef(); currentSubSchema == nil {
+		return nil
+	}
+
+	switch node := node.(type) {
+
+	case map[string]interface{}:
+		for _, pSchema := range currentSubSchema.propertiesChildren {
+			if value, ok := node[pSchema.property]; ok {
+				err := applyDefaults(pSchema, value, injecting)
+				if err != nil {
+					return err
+				}
+				continue
+			}
+
+			defaultSchema := pSchema.resolvedRef()
+			if defaultSchema == nil || defaultSchema.defaultValue == nil || injecting[defaultSchema] {
+				continue
+			}
+
+			value, err := decodeJSONUseNumber([]byte(*defaultSchema.defaultValue))
+			if err != nil {
+				return err
+			}
+			injecting[defaultSchema] = true
+			err = applyDefaults(defaultSchema, value, injecting)
+			delete(injecting, defaultSchema)
+			if err != nil {
+				return err
+			}
+			node[pSchema.property] = value
+		}
+
+	case []interface{}:
+		for i, item := range node {
+			var itemSchema *subSchema
+			if currentSubSchema.itemsChildrenIsSingleSchema {
+				itemSchema = currentSubSchema.itemsChildren[0]
+			} else if i < len(currentSubSchema.itemsChildren) {
+				itemSchema = currentSubSchema.itemsChildren[i]
+			} else if additionalItemSchema, ok := currentSubSchema.additionalItems.(*subSchema); ok {
+				itemSchema = additionalItemSchema
+			}
+			if itemSchema != nil {
+				err := applyDefaults(itemSchema, item, injecting)
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,105 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for the normalized documents.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidateNormalize(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"count": {"type": "integer"},
+			"unit": {"default": "kg"},
+			"lines": {
+				"items": {"properties": {"quantity": {"$ref": "#/definitions/quantity"}}}
+			}
+		},
+		"definitions": {"quantity": {"default": 1}}
+	}`))
+	assert.Nil(t, err)
+
+	normalized, result, err := schema.ValidateNormalize(NewStringLoader(`{"count": 3, "lines": [{}, {"quantity": 2.5}]}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+	assert.Equal(t, map[string]interface{}{
+		"count": json.Number("3"),
+		"unit":  "kg",
+		"lines": []interface{}{
+			map[string]interface{}{"quantity": json.Number("1")},
+			map[string]interface{}{"quantity": json.Number("2.5")},
+		},
+	}, normalized)
+
+	_, result, err = schema.ValidateNormalize(NewStringLoader(`{"count": 3.5}`))
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+}
+
+func TestValidateNormalizeRecursiveDefault(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"definitions": {
+			"node": {
+				"type": "object",
+				"properties": {"child": {"$ref": "#/definitions/node"}, "name": {"default": "leaf"}},
+				"default": {}
+			}
+		},
+		"$ref": "#/definitions/node"
+	}`))
+	assert.Nil(t, err)
+
+	// the default of node is not set again inside itself
+	normalized, result, err := schema.ValidateNormalize(NewStringLoader(`{}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+	assert.Equal(t, map[string]interface{}{
+		"name":  "leaf",
+		"child": map[string]interface{}{"name": "leaf"},
+	}, normalized)
+
+	normalized, _, err = schema.ValidateNormalize(NewStringLoader(`{"child": {"child": {}}}`))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{
+		"name": "leaf",
+		"child": map[string]interface{}{
+			"name":  "leaf",
+			"child": map[string]interface{}{"name": "leaf", "child": map[string]interface{}{"name": "leaf"}},
+		},
+	}, normalized)
+
+	// a circular $ref has no default
+	schema, err = NewSchema(NewStringLoader(`{"properties": {"a": {"$ref": "#/definitions/b"}}, "definitions": {"b": {"$ref": "#/definitions/c"}, "c": {"$ref": "#/definitions/b"}}}`))
+	assert.Nil(t, err)
+	normalized, _, err = schema.ValidateNormalize(NewStringLoader(`{}`))
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{}, normalized)
+}
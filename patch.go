@@ -0,0 +1,352 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      Validation of the parts of a document changed by a JSON Patch.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// Operations of a JSON Patch
+const (
+	PATCH_ADD     = "add"
+	PATCH_REMOVE  = "remove"
+	PATCH_REPLACE = "replace"
+	PATCH_MOVE    = "move"
+	PATCH_COPY    = "copy"
+	PATCH_TEST    = "test"
+)
+
+// PatchOp is an operation of a JSON Patch, as defined by RFC 6902
+type PatchOp struct {
+	Op    string      `json:"op"`
+	Path  string      `json:"path"`
+	From  string      `json:"from,omitempty"`
+	Value interface{} `json:"value,omitempty"`
+}
+
+// ValidatePatched validates a document to which a JSON Patch was applied,
+// given the patched document. Only the nodes the patch changed, along with
+// their ancestors, are validated : the document is expected to have been
+// valid before the patch, so that the other nodes are still valid.
+// The keywords of the ancestors are checked, ex required or uniqueItems, and
+// the anyOf, oneOf and not of the ancestors are validated in full, as their
+// branches may depend on any part of the node.
+func (v *Schema) ValidatePatched(l JSONLoader, patch []PatchOp) (*Result, error) {
+
+	var options ValidateOptions
+	root, err := loadDocument(l, &options, v)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := &changedPaths{}
+	for _, op := range patch {
+		var paths []string
+		switch op.Op {
+		case PATCH_ADD, PATCH_REMOVE, PATCH_REPLACE, PATCH_COPY:
+			paths = []string{op.Path}
+		case PATCH_MOVE:
+			paths = []string{op.From, op.Path}
+		case PATCH_TEST:
+		default:
+			return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_INVALID_PATCH_OPERATION_X, op.Op))
+		}
+		for _, path := range paths {
+			if err := changes.add(root, path, op.Op != PATCH_REPLACE); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if changes.whole {
+		changes = nil
+	}
+
+	return validateRoot(v.rootSchema, root, options, changes, nil), nil
+}
+
+// The nodes of a document changed by a JSON Patch, as a tree of their keys
+// and indexes
+type changedPaths struct {
+	whole    bool // the node changed, with all its descendants
+	children map[string]*changedPaths
+}
+
+// Records the change of the node at a JSON pointer of the patched document.
+// Adding or removing an item shifts the following ones, which changes the
+// whole array. Missing nodes change their closest ancestor.
+func (c *changedPaths) add(root interface{}, pointer string, shifts bool) error {
+
+	if pointer != "" && !strings.HasPrefix(pointer, "/") {
+		return errors.New(fmt.Sprintf(ERROR_MESSAGE_INVALID_PATCH_PATH_X, pointer))
+	}
+
+	var tokens []string
+	if pointer != "" {
+		tokens = strings.Split(pointer[1:], "/")
+	}
+
+	changed, node := c, root
+	for i, token := range tokens {
+		token = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+		last := i == len(tokens)-1
+
+		var child interface{}
+		found := false
+		switch n := node.(type) {
+		case map[string]interface{}:
+			// a removed property is no longer in the document, its path is
+			// recorded all the same
+			child, found = n[token]
+			found = found || last
+		case []interface{}:
+			index, err := strconv.Atoi(token)
+			if err == nil && index >= 0 && index < len(n) && !(shifts && last) {
+				child, found = n[index], true
+			}
+		}
+		if !found {
+			break
+		}
+
+		if changed.children == nil {
+			changed.children = make(map[string]*changedPaths)
+		}
+		if changed.children[token] == nil {
+			changed.children[token] = &changedPaths{}
+		}
+		changed, node = changed.children[token], child
+	}
+
+	changed.whole = true
+	return nil
+}
+
+// Tells whether the node at a context changed or has changed descendants
+func (c *changedPaths) touches(context *JSONContext) bool {
+
+	changed := c
+	for _, segment := range context.Segments() {
+		if changed.whole {
+			return true
+		}
+		if changed = changed.children[segment]; changed == nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Applies a JSON Patch to a decoded JSON document, which is modified in place,
+// and returns the patched document. Each operation applies to the document
+// patched by the previous ones.
+func applyPatch(document interface{}, patch []PatchOp) (interface{}, error) {
+
+	for _, op := range patch {
+		var err error
+		switch op.Op {
+		case PATCH_ADD:
+			document, err = patchAdd(document, op.Path, copyJSON(op.Value))
+		case PATCH_REMOVE:
+			document, err = patchAt(document, op.Path, patchRemove)
+		case PATCH_REPLACE:
+			if op.Path == "" {
+				document = copyJSON(op.Value)
+			} else if document, err = patchAt(document, op.Path, patchRemove); err == nil {
+				document, err = patchAdd(document, op.Path, copyJSON(op.Value))
+			}
+		case PATCH_MOVE, PATCH_COPY:
+			var value interface{}
+			if value, err = patchGet(document, op.From); err != nil {
+				break
+			}
+			if op.Op == PATCH_MOVE {
+				// a node cannot be moved into one of its children
+				if strings.HasPrefix(op.Path, op.From+"/") {
+					return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_INVALID_PATCH_PATH_X, op.Path))
+				}
+				if document, err = patchAt(document, op.From, patchRemove); err != nil {
+					break
+				}
+			} else {
+				value = copyJSON(value)
+			}
+			document, err = patchAdd(document, op.Path, value)
+		case PATCH_TEST:
+			var value interface{}
+			if value, err = patchGet(document, op.Path); err == nil && !jsonDeepEqual(value, op.Value) {
+				err = errors.New(fmt.Sprintf(ERROR_MESSAGE_PATCH_TEST_FAILED_AT_X, op.Path))
+			}
+		default:
+			err = errors.New(fmt.Sprintf(ERROR_MESSAGE_INVALID_PATCH_OPERATION_X, op.Op))
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return document, nil
+}
+
+// Splits a JSON pointer into its unescaped tokens
+func patchTokens(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_INVALID_PATCH_PATH_X, pointer))
+	}
+	tokens := strings.Split(pointer[1:], "/")
+	for i, token := range tokens {
+		tokens[i] = strings.Replace(strings.Replace(token, "~1", "/", -1), "~0", "~", -1)
+	}
+	return tokens, nil
+}
+
+// Returns the node at a JSON pointer, an error when it does not exist
+func patchGet(document interface{}, pointer string) (interface{}, error) {
+	tokens, err := patchTokens(pointer)
+	if err != nil {
+		return nil, err
+	}
+	node := document
+	for _, token := range tokens {
+		var ok bool
+		if node, ok = patchChild(node, token); !ok {
+			return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_INVALID_PATCH_PATH_X, pointer))
+		}
+	}
+	return node, nil
+}
+
+// Returns the existing property or item of a node
+func patchChild(node interface{}, token string) (interface{}, bool) {
+	switch n := node.(type) {
+	case map[string]interface{}:
+		child, ok := n[token]
+		return child, ok
+	case []interface{}:
+		index, err := strconv.Atoi(token)
+		if err != nil || index < 0 || index >= len(n) {
+			return nil, false
+		}
+		return n[index], true
+	}
+	return nil, false
+}
+
+// Changes the child of the node holding the last token of the pointer, and
+// returns the document, in which arrays may have been replaced as they grew
+// or shrank. The root cannot be changed.
+func patchAt(document interface{}, pointer string, change func(parent interface{}, token string) (interface{}, bool)) (interface{}, error) {
+
+	tokens, err := patchTokens(pointer)
+	if err != nil {
+		return nil, err
+	}
+
+	var walk func(node interface{}, tokens []string) (interface{}, bool)
+	walk = func(node interface{}, tokens []string) (interface{}, bool) {
+		if len(tokens) == 1 {
+			return change(node, tokens[0])
+		}
+		child, ok := patchChild(node, tokens[0])
+		if !ok {
+			return nil, false
+		}
+		if child, ok = walk(child, tokens[1:]); !ok {
+			return nil, false
+		}
+		switch n := node.(type) {
+		case map[string]interface{}:
+			n[tokens[0]] = child
+		case []interface{}:
+			index, _ := strconv.Atoi(tokens[0])
+			n[index] = child
+		}
+		return node, true
+	}
+
+	if len(tokens) == 0 {
+		return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_INVALID_PATCH_PATH_X, pointer))
+	}
+	document, ok := walk(document, tokens)
+	if !ok {
+		return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_INVALID_PATCH_PATH_X, pointer))
+	}
+	return document, nil
+}
+
+// Adds a property, or inserts an item before the one at the index, "-"
+// appending it. The root is replaced by the value.
+func patchAdd(document interface{}, pointer string, value interface{}) (interface{}, error) {
+	if pointer == "" {
+		return value, nil
+	}
+	return patchAt(document, pointer, func(parent interface{}, token string) (interface{}, bool) {
+		switch p := parent.(type) {
+		case map[string]interface{}:
+			p[token] = value
+			return p, true
+		case []interface{}:
+			index := len(p)
+			if token != "-" {
+				var err error
+				if index, err = strconv.Atoi(token); err != nil || index < 0 || index > len(p) {
+					return nil, false
+				}
+			}
+			p = append(p, nil)
+			copy(p[index+1:], p[index:])
+			p[index] = value
+			return p, true
+		}
+		return nil, false
+	})
+}
+
+// Removes a property or an item
+func patchRemove(parent interface{}, token string) (interface{}, bool) {
+	switch p := parent.(type) {
+	case map[string]interface{}:
+		if _, ok := p[token]; !ok {
+			return nil, false
+		}
+		delete(p, token)
+		return p, true
+	case []interface{}:
+		index, err := strconv.Atoi(token)
+		if err != nil || index < 0 || index >= len(p) {
+			return nil, false
+		}
+		return append(p[:index], p[index+1:]...), true
+	}
+	return nil, false
+}
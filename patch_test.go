@@ -0,0 +1,136 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for the validation of patched documents.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidatePatched(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"a": {"type": "string"},
+			"b": {"type": "string"},
+			"list": {"items": {"type": "integer"}, "uniqueItems": true},
+			"obj": {
+				"required": ["x"],
+				"properties": {"x": {"type": "integer"}, "y": {"type": "integer"}}
+			},
+			"choice": {"oneOf": [{"properties": {"p": {"type": "string"}}}, {"properties": {"q": {"type": "string"}}}]}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	// the invalid "b" and "list/1" did not change, they are skipped
+	document := `{"a": 1, "b": 2, "list": [1, 2.5], "obj": {"x": 1, "y": "z"}, "choice": {"p": 1, "q": 1}}`
+
+	cases := []struct {
+		patch    []PatchOp
+		contexts []string
+	}{
+		{[]PatchOp{{Op: PATCH_REPLACE, Path: "/a", Value: 1}}, []string{"#/a"}},
+		{[]PatchOp{{Op: PATCH_TEST, Path: "/a", Value: 1}}, nil},
+		{[]PatchOp{{Op: PATCH_REMOVE, Path: "/obj/x"}}, nil}, // the document still has x
+		{[]PatchOp{{Op: PATCH_REPLACE, Path: "/obj/x", Value: 1}}, nil},
+		{[]PatchOp{{Op: PATCH_REPLACE, Path: "/obj/y", Value: "z"}}, []string{"#/obj/y"}},
+		{[]PatchOp{{Op: PATCH_MOVE, From: "/a", Path: "/obj/y"}}, []string{"#/a", "#/obj/y"}},
+		// adding an item shifts the others, the whole array is validated
+		{[]PatchOp{{Op: PATCH_ADD, Path: "/list/0", Value: 2}}, []string{"#/list/1"}},
+		{[]PatchOp{{Op: PATCH_REPLACE, Path: "/list/0", Value: 2}}, nil},
+		// oneOf depends on the whole node
+		{[]PatchOp{{Op: PATCH_REPLACE, Path: "/choice/p", Value: 1}}, []string{"#/choice"}},
+		{[]PatchOp{{Op: PATCH_REPLACE, Path: "", Value: nil}}, []string{"#/a", "#/b", "#/list/1", "#/obj/y", "#/choice"}},
+	}
+
+	for _, c := range cases {
+		result, err := schema.ValidatePatched(NewStringLoader(document), c.patch)
+		assert.Nil(t, err)
+		var contexts []string
+		for _, rerr := range result.Errors() {
+			contexts = append(contexts, rerr.Context.String())
+		}
+		assert.ElementsMatch(t, c.contexts, contexts, "%v", c.patch)
+	}
+
+	// the ancestors of a change are checked
+	result, err := schema.ValidatePatched(NewStringLoader(`{"obj": {"y": 1}, "list": [1, 1]}`), []PatchOp{
+		{Op: PATCH_REMOVE, Path: "/obj/x"},
+		{Op: PATCH_REPLACE, Path: "/list/1", Value: 1},
+	})
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 2) {
+		assert.ElementsMatch(t, []string{KEY_REQUIRED, KEY_UNIQUE_ITEMS}, []string{result.Errors()[0].Reason, result.Errors()[1].Reason})
+	}
+
+	_, err = schema.ValidatePatched(NewStringLoader(document), []PatchOp{{Op: "rename", Path: "/a"}})
+	assert.EqualError(t, err, `Invalid JSON Patch operation "rename"`)
+	_, err = schema.ValidatePatched(NewStringLoader(document), []PatchOp{{Op: PATCH_REMOVE, Path: "a"}})
+	assert.EqualError(t, err, `Invalid JSON Patch path "a"`)
+}
+
+func TestApplyPatch(t *testing.T) {
+
+	decode := func(s string) interface{} {
+		document, err := decodeJSONUseNumber([]byte(s))
+		assert.Nil(t, err)
+		return document
+	}
+
+	document := `{"a": {"b": [1, 2, 3]}, "c": "x", "~/": 0}`
+	for _, test := range []struct {
+		patch    []PatchOp
+		expected string
+		err      string
+	}{
+		{[]PatchOp{{Op: PATCH_ADD, Path: "/d", Value: true}}, `{"a": {"b": [1, 2, 3]}, "c": "x", "~/": 0, "d": true}`, ""},
+		{[]PatchOp{{Op: PATCH_ADD, Path: "/a/b/1", Value: 9}, {Op: PATCH_ADD, Path: "/a/b/-", Value: 8}}, `{"a": {"b": [1, 9, 2, 3, 8]}, "c": "x", "~/": 0}`, ""},
+		{[]PatchOp{{Op: PATCH_REMOVE, Path: "/a/b/0"}, {Op: PATCH_REMOVE, Path: "/~0~1"}}, `{"a": {"b": [2, 3]}, "c": "x"}`, ""},
+		{[]PatchOp{{Op: PATCH_REPLACE, Path: "/c", Value: "y"}, {Op: PATCH_REPLACE, Path: "/a/b/2", Value: 0}}, `{"a": {"b": [1, 2, 0]}, "c": "y", "~/": 0}`, ""},
+		{[]PatchOp{{Op: PATCH_MOVE, From: "/c", Path: "/a/c"}}, `{"a": {"b": [1, 2, 3], "c": "x"}, "~/": 0}`, ""},
+		{[]PatchOp{{Op: PATCH_COPY, From: "/a/b", Path: "/e"}, {Op: PATCH_ADD, Path: "/e/0", Value: 0}}, `{"a": {"b": [1, 2, 3]}, "c": "x", "~/": 0, "e": [0, 1, 2, 3]}`, ""},
+		{[]PatchOp{{Op: PATCH_TEST, Path: "/a/b/0", Value: 1.0}}, document, ""},
+		{[]PatchOp{{Op: PATCH_REPLACE, Path: "", Value: []interface{}{}}}, `[]`, ""},
+		{[]PatchOp{{Op: PATCH_TEST, Path: "/c", Value: "y"}}, "", `JSON Patch test failed at "/c"`},
+		{[]PatchOp{{Op: PATCH_REMOVE, Path: "/d"}}, "", `Invalid JSON Patch path "/d"`},
+		{[]PatchOp{{Op: PATCH_REPLACE, Path: "/a/b/3", Value: 0}}, "", `Invalid JSON Patch path "/a/b/3"`},
+		{[]PatchOp{{Op: PATCH_ADD, Path: "/a/x/y", Value: 0}}, "", `Invalid JSON Patch path "/a/x/y"`},
+		{[]PatchOp{{Op: PATCH_MOVE, From: "/a", Path: "/a/d"}}, "", `Invalid JSON Patch path "/a/d"`},
+		{[]PatchOp{{Op: PATCH_REMOVE, Path: ""}}, "", `Invalid JSON Patch path ""`},
+		{[]PatchOp{{Op: "rename", Path: "/c"}}, "", `Invalid JSON Patch operation "rename"`},
+	} {
+		patched, err := applyPatch(decode(document), test.patch)
+		if test.err != "" {
+			assert.EqualError(t, err, test.err)
+			continue
+		}
+		if assert.Nil(t, err) {
+			assert.True(t, jsonDeepEqual(decode(test.expected), patched), "%v", test.patch)
+		}
+	}
+}
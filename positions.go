@@ -0,0 +1,141 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      Locates the errors of a validation in the source of the document.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"encoding/json"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Byte offsets of the values of a JSON source, keyed by their path
+type sourcePositions struct {
+	source     string
+	offsets    map[string]int
+	lineStarts []int
+}
+
+// Separates the segments of a path in the keys of sourcePositions.offsets,
+// a character that cannot appear unescaped in JSON
+const positionPathSeparator = "\x00"
+
+func newSourcePositions(source string) (*sourcePositions, error) {
+
+	p := &sourcePositions{source: source, offsets: make(map[string]int), lineStarts: []int{0}}
+
+	for i := 0; i < len(source); i++ {
+		if source[i] == '\n' {
+			p.lineStarts = append(p.lineStarts, i+1)
+		}
+	}
+
+	decoder := json.NewDecoder(strings.NewReader(source))
+	if err := p.scanValue(decoder, nil); err != nil {
+		return nil, err
+	}
+
+	return p, nil
+}
+
+// Records the offset of the next value of the decoder, and of its children
+func (p *sourcePositions) scanValue(decoder *json.Decoder, path []string) error {
+
+	p.offsets[strings.Join(path, positionPathSeparator)] = p.skipSeparators(int(decoder.InputOffset()))
+
+	token, err := decoder.Token()
+	if err != nil {
+		return err
+	}
+
+	switch token {
+
+	case json.Delim('{'):
+		for decoder.More() {
+			key, err := decoder.Token()
+			if err != nil {
+				return err
+			}
+			if err := p.scanValue(decoder, append(path[:len(path):len(path)], key.(string))); err != nil {
+				return err
+			}
+		}
+		_, err = decoder.Token()
+
+	case json.Delim('['):
+		for i := 0; decoder.More(); i++ {
+			if err := p.scanValue(decoder, append(path[:len(path):len(path)], strconv.Itoa(i))); err != nil {
+				return err
+			}
+		}
+		_, err = decoder.Token()
+	}
+
+	return err
+}
+
+// Skips the blanks, colons and commas preceding a value
+func (p *sourcePositions) skipSeparators(offset int) int {
+	for offset < len(p.source) && strings.IndexByte(" \t\r\n:,", p.source[offset]) >= 0 {
+		offset++
+	}
+	return offset
+}
+
+// Returns the line and column, both starting at 1, of the node at context or,
+// when it is missing from the source ( ex a required property ), of its closest
+// ancestor. The column counts unicode code points.
+func (p *sourcePositions) locate(context *JSONContext) (line int, column int) {
+
+	segments := context.Segments()
+	for {
+		if offset, ok := p.offsets[strings.Join(segments, positionPathSeparator)]; ok {
+			line = sort.Search(len(p.lineStarts), func(i int) bool { return p.lineStarts[i] > offset })
+			column = utf8.RuneCountInString(p.source[p.lineStarts[line-1]:offset]) + 1
+			return line, column
+		}
+		if len(segments) == 0 {
+			return 0, 0
+		}
+		segments = segments[:len(segments)-1]
+	}
+}
+
+// Sets the position of the errors of a result validated from source
+func (v *Result) setErrorsPosition(source string) error {
+
+	positions, err := newSourcePositions(source)
+	if err != nil {
+		return err
+	}
+
+	for i := range v.errors {
+		v.errors[i].Line, v.errors[i].Column = positions.locate(v.errors[i].Context)
+	}
+
+	return nil
+}
@@ -0,0 +1,76 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for the positions of the errors in the source of a document.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackPositions(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"name": {"type": "string"},
+			"tags": {"items": {"type": "string"}},
+			"owner": {"required": ["id"]}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	source := "{\n" +
+		"  \"name\" : 42,\n" +
+		"  \"tags\": [\"é\", \"ü\",\n" +
+		"           7],\n" +
+		"  \"owner\":{}\n" +
+		"}"
+
+	result, err := schema.Validate(NewStringLoader(source))
+	assert.Nil(t, err)
+	for _, resultError := range result.Errors() {
+		assert.Equal(t, 0, resultError.Line)
+	}
+
+	result, err = schema.ValidateWithOptions(NewStringLoader(source), ValidateOptions{TrackPositions: true})
+	assert.Nil(t, err)
+	positions := make(map[string][2]int)
+	for _, resultError := range result.Errors() {
+		positions[resultError.Context.String()] = [2]int{resultError.Line, resultError.Column}
+	}
+	assert.Equal(t, map[string][2]int{
+		"#/name":     {2, 12},
+		"#/tags/2":   {4, 12},
+		"#/owner/id": {5, 11}, // missing, located at its parent
+	}, positions)
+
+	// only string loaders have a source
+	result, err = schema.ValidateWithOptions(NewGoLoader(map[string]interface{}{"name": 1}), ValidateOptions{TrackPositions: true})
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, 0, result.Errors()[0].Line)
+	}
+}
@@ -26,53 +26,225 @@
 package gojsonschema
 
 import (
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"reflect"
 	"sort"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/xeipuuv/gojsonpointer"
 )
 
 type ResultError struct {
 	Context *JSONContext // Tree like notation of the part that failed the validation. ex (root).a.b ...
 	Value   interface{}  // Value given by the JSON file that is the source of the error
 
-	Reason      string      //JSON schema keyword responsible for this error
-	Requirement interface{} // the schema attribute's requirement that caused this error
+	Reason      string                 //JSON schema keyword responsible for this error
+	Requirement interface{}            // the schema attribute's requirement that caused this error
+	Details     map[string]interface{} // additional information about the error, keyed by name
+	Title       string                 // title of the subSchema of the failing field, see ValidateOptions.UseTitleInErrors
+	Message     string                 // message given to the error by the schema, see the x-errorMessage extension
+	Line        int                    // line of the failing field in the source of the document, see ValidateOptions.TrackPositions
+	Column      int                    // column of the failing field in the source of the document, see ValidateOptions.TrackPositions
 }
 
 func (v ResultError) String() string {
+
+	field := v.Context.String()
+	if v.Title != "" {
+		field = v.Title
+	}
+
+	return fmt.Sprintf("%s: %s", field, v.description())
+}
+
+// The message of the error, or its reason followed by the requirement,
+// ex minimum,18
+func (v ResultError) description() string {
+	if v.Message != "" {
+		return v.Message
+	}
 	var l []string
 	l = append(l, fmt.Sprintf("%s", v.Reason))
 	if v.Requirement != nil {
-		l = append(l, fmt.Sprintf("%s", v.Requirement))
+		// numeric requirements are pointers to the values of the subSchema
+		requirement := reflect.ValueOf(v.Requirement)
+		if requirement.Kind() == reflect.Ptr && !requirement.IsNil() {
+			requirement = requirement.Elem()
+		}
+		l = append(l, fmt.Sprintf("%v", requirement.Interface()))
+	}
+
+	return strings.Join(l, ",")
+}
+
+// StructuredError is a flat form of a ResultError holding strings only, so
+// that it maps directly to the messages of other formats, like protobuf ones.
+// Its fields are kept as they are across versions.
+type StructuredError struct {
+	Path    string            // JSON pointer to the failing field, "" for the root, ex /items/0
+	Keyword string            // keyword responsible for the error, ex minimum
+	Message string            // reason and requirement, ex minimum,18, or the message of the error
+	Params  map[string]string // requirement, value and details of the error, as JSON
+}
+
+// Structured returns the errors as StructuredErrors. The requirement and the
+// value of each error are put in Params, under "requirement" and "value" when
+// they are given, along with its details.
+func (rerrs ResultErrors) Structured() []StructuredError {
+
+	structured := make([]StructuredError, 0, len(rerrs))
+	for _, rerr := range rerrs {
+		params := make(map[string]string, len(rerr.Details)+2)
+		for name, detail := range rerr.Details {
+			params[name] = structuredParam(detail)
+		}
+		if rerr.Requirement != nil {
+			requirement := reflect.ValueOf(rerr.Requirement)
+			if requirement.Kind() == reflect.Ptr && !requirement.IsNil() {
+				requirement = requirement.Elem()
+			}
+			params[STRING_REQUIREMENT] = structuredParam(requirement.Interface())
+		}
+		if rerr.Value != emptyProperty {
+			params[STRING_VALUE] = structuredParam(rerr.Value)
+		}
+		structured = append(structured, StructuredError{
+			Path:    rerr.Context.Pointer(),
+			Keyword: rerr.Reason,
+			Message: rerr.description(),
+			Params:  params,
+		})
 	}
 
-	return fmt.Sprintf("%s: %s", v.Context.String(), strings.Join(l, ","))
+	return structured
+}
+
+// Encodes a parameter of a StructuredError as JSON, or as %v when it cannot be
+func structuredParam(param interface{}) string {
+	encoded, err := json.Marshal(param)
+	if err != nil {
+		return fmt.Sprintf("%v", param)
+	}
+	return string(encoded)
+}
+
+// ElidedValue stands for an object or an array in ResultError.Value, see
+// ValidateOptions.ElideValues
+type ElidedValue struct {
+	Pointer string // JSON pointer to the value in the document, ex /a/0
+	Type    string // TYPE_OBJECT or TYPE_ARRAY
+	Length  int    // number of properties or items
+}
+
+// ResolveValue returns the value of the error, looking it up in the validated
+// document root when it was elided. nil if the value is not found in root.
+func (v ResultError) ResolveValue(root interface{}) interface{} {
+
+	elided, ok := v.Value.(ElidedValue)
+	if !ok {
+		return v.Value
+	}
+
+	pointer, err := gojsonpointer.NewJsonPointer(elided.Pointer)
+	if err != nil {
+		return nil
+	}
+	value, _, err := pointer.Get(root)
+	if err != nil {
+		return nil
+	}
+
+	return value
 }
 
 // sort by score descending
 type resultsByScore []*Result
 
-func (r resultsByScore) Len() int           { return len(r) }
-func (r resultsByScore) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
-func (r resultsByScore) Less(i, j int) bool { return r[i].score > r[j].score }
+func (r resultsByScore) Len() int      { return len(r) }
+func (r resultsByScore) Swap(i, j int) { r[i], r[j] = r[j], r[i] }
+func (r resultsByScore) Less(i, j int) bool {
+	if r[i].score == r[j].score {
+		return len(r[i].errors) < len(r[j].errors)
+	}
+	return r[i].score > r[j].score
+}
 
-// returns the best result based on the highest non repeating score.
+// returns the best result based on the highest non repeating score,
+// the number of errors breaking ties.
 func getBestResult(results resultsByScore) *Result {
+	if len(results) == 1 {
+		return results[0]
+	}
 	if len(results) > 1 {
 		sort.Sort(results)
-		if results[0].score != results[1].score {
+		if results[0].score != results[1].score || len(results[0].errors) != len(results[1].errors) {
 			return results[0]
 		}
 	}
 	return nil
 }
 
+// Keeps the best of a series of results as getBestResult would, without
+// holding on to the others
+type bestResult struct {
+	best *Result
+	tied bool
+}
+
+func (b *bestResult) add(result *Result) {
+	switch {
+	case b.best == nil || resultsByScore([]*Result{result, b.best}).Less(0, 1):
+		b.best, b.tied = result, false
+	case !resultsByScore([]*Result{b.best, result}).Less(0, 1):
+		b.tied = true
+	}
+}
+
+// nil when no result was added or the best ones are tied
+func (b *bestResult) get() *Result {
+	if b.tied {
+		return nil
+	}
+	return b.best
+}
+
 type Result struct {
 	errors []ResultError
 	// Scores how well the validation matched. Useful in generating
-	// better error messages for anyOf and oneOf.
+	// better error messages for anyOf and oneOf. Not kept when the schema has
+	// neither, see BenchmarkValidateWideSchema.
 	score int
+	// Options the validation was started with, shared by the sub results.
+	options *ValidateOptions
+	// Validated document, when captured.
+	document interface{}
+	// Annotations, failures that do not invalidate the document.
+	annotations []ResultError
+	// Locations of the subSchemas that matched, shared by the sub results.
+	// nil unless ValidateOptions.TrackCoverage is set.
+	coverage map[string]bool
+	// Keys of the objects evaluated by properties, patternProperties and
+	// additionalProperties, by JSON pointer to the object, shared by the sub
+	// results. nil unless ValidateOptions.TrackEvaluatedProperties is set.
+	evaluated map[string]map[string]bool
+	// Most specific subSchema that validated each node, by JSON pointer, shared
+	// by the sub results. nil unless ValidateOptions.TrackAppliedSchemas is set.
+	applied map[string]*subSchema
+	// Time spent by function and keyword, shared by the sub results. nil unless
+	// ValidateOptions.Profile is set.
+	profile map[string]time.Duration
+	// Nodes to validate, the others being skipped, see Schema.ValidatePatched.
+	// nil to validate the whole document.
+	changes *changedPaths
+	// nil unless ValidateOptions.Deadline is set, shared by the sub results.
+	deadline *deadlineCheck
+	// Subschemas validated in place of those $ref point to, by location,
+	// see Schema.ValidateWithOverrides. Shared by the sub results.
+	overrides map[string]*subSchema
 }
 
 func (v *Result) Valid() bool {
@@ -83,6 +255,93 @@ func (v *Result) Errors() ResultErrors {
 	return v.errors
 }
 
+// Err returns the errors as an error, nil when the document is valid.
+// The error is the ResultErrors of the result, which errors.As retrieves.
+func (v *Result) Err() error {
+	if v.Valid() {
+		return nil
+	}
+	return ResultErrors(v.errors)
+}
+
+// Annotations returns the failures that do not make the document invalid,
+// like the format mismatches when the StrictFormat option is not set.
+// As for the errors, the annotations of the discarded anyOf and oneOf
+// branches are dropped.
+func (v *Result) Annotations() ResultErrors {
+	return v.annotations
+}
+
+// Document returns the validated document when the validation was started
+// with the CaptureDocument option, nil otherwise.
+func (v *Result) Document() interface{} {
+	return v.document
+}
+
+// CoveredSchemas returns the sorted locations of the subSchemas that a node of the
+// document matched, when the validation was started with the TrackCoverage
+// option. The branches of anyOf and oneOf count even when the keyword fails.
+func (v *Result) CoveredSchemas() []string {
+	return sortedKeys(v.coverage)
+}
+
+// EvaluatedProperties returns the sorted keys of the object at a JSON pointer
+// of the document, ex "/items/0" or "#/items/0", that properties,
+// patternProperties or additionalProperties evaluated, when the validation
+// was started with the TrackEvaluatedProperties option. As for CoveredSchemas,
+// the keys evaluated by the branches of anyOf and oneOf count even when the
+// keyword fails.
+func (v *Result) EvaluatedProperties(pointer string) []string {
+	return sortedKeys(v.evaluated[strings.TrimPrefix(pointer, "#")])
+}
+
+// AppliedSchema returns the most specific subSchema that the node at a JSON
+// pointer of the document, ex "/items/3" or "#/items/3", was valid against,
+// when the validation was started with the TrackAppliedSchemas option : the
+// subSchema a $ref points to rather than the one holding the $ref, an allOf
+// subSchema rather than the one holding the allOf. The subSchemas nested in
+// a subSchema the node fails do not apply. false when none applies.
+// The subSchema belongs to the validated schema, its setters must not be used.
+func (v *Result) AppliedSchema(pointer string) (*subSchema, bool) {
+	s, ok := v.applied[strings.TrimPrefix(pointer, "#")]
+	return s, ok
+}
+
+// Profile returns the time spent in the validation functions, by name as
+// "validateString", and in the costliest keywords, by name as "pattern", when
+// the validation was started with the Profile option. The time of the
+// functions and keywords validating nested values, as validateArray or anyOf,
+// includes the time spent validating these values.
+func (v *Result) Profile() map[string]time.Duration {
+	return v.profile
+}
+
+// Starts timing a function or a keyword, the returned function stops it.
+// Nothing is timed unless ValidateOptions.Profile is set.
+func (v *Result) startTiming(name string) func() {
+	if v.profile == nil {
+		return noTiming
+	}
+	start := time.Now()
+	return func() {
+		v.profile[name] += time.Since(start)
+	}
+}
+
+func noTiming() {}
+
+// Records that the key of the object at context was evaluated
+func (v *Result) evaluateProperty(context *JSONContext, key string) {
+	if v.evaluated == nil {
+		return
+	}
+	pointer := context.Pointer()
+	if v.evaluated[pointer] == nil {
+		v.evaluated[pointer] = make(map[string]bool)
+	}
+	v.evaluated[pointer][key] = true
+}
+
 // AddError adds a context JSON schema error to Result using the failing schema
 // attribute as the reason
 func (v *Result) AddError(
@@ -90,25 +349,163 @@ func (v *Result) AddError(
 	reason string,
 	requirement interface{},
 	value interface{},
+) {
+	v.addError(context, reason, requirement, value, nil)
+}
+
+func (v *Result) addError(
+	context *JSONContext,
+	reason string,
+	requirement interface{},
+	value interface{},
+	details map[string]interface{},
 ) {
 	rerr := ResultError{
 		Context:     context,
 		Reason:      reason,
 		Requirement: requirement,
 		Value:       value,
+		Details:     details,
+	}
+	if v.options != nil && v.options.Observer != nil {
+		v.options.Observer.OnError(rerr)
 	}
 	v.errors = append(v.errors, rerr)
-	v.score -= 2 // results in a net -1 when added to the +1 we get at the end of the validation function
+	if v.scored() {
+		v.score -= 2 // results in a net -1 when added to the +1 we get at the end of the validation function
+	}
+}
+
+// Adds a detail to every error of the result
+func (v *Result) setErrorsDetail(name string, value interface{}) {
+	for i := range v.errors {
+		if v.errors[i].Details == nil {
+			v.errors[i].Details = make(map[string]interface{})
+		}
+		v.errors[i].Details[name] = value
+	}
+}
+
+// Returns the number of decimals of a number of the document, as written in
+// its JSON text when it is known, see x-maxDecimals
+func (v *Result) decimalPlaces(f float64, context *JSONContext) int {
+	if text, ok := v.options.numberTexts[context.Pointer()]; ok {
+		return decimalPlacesOfText(text)
+	}
+	return decimalPlaces(f)
+}
+
+// Keywords of an object or array reporting their errors at one of its
+// properties or items, rather than at the node itself
+var childErrorKeywords = map[string]bool{
+	KEY_REQUIRED:              true,
+	KEY_ADDITIONAL_PROPERTIES: true,
+	KEY_DEPENDENCIES:          true,
+	KEY_X_SORTED:              true,
+	KEY_X_PROPERTY_ORDER:      true,
+}
+
+// Sets the message x-errorMessage gives to the errors about the node at
+// context that have none yet, starting from the error at index from, so that
+// the messages of the nested subSchemas come first. Nothing is done when the
+// contexts are not tracked.
+func (v *Result) setErrorsMessage(s *subSchema, context *JSONContext, from int) {
+	if context == nil {
+		return
+	}
+	for i := from; i < len(v.errors); i++ {
+		rerr := &v.errors[i]
+		if rerr.Message != "" {
+			continue
+		}
+		if rerr.Context != context && (rerr.Context.tail != context || !childErrorKeywords[rerr.Reason]) {
+			continue
+		}
+		if message, ok := s.errorMessageFor(rerr.Reason); ok {
+			rerr.Message = message
+		}
+	}
+}
+
+// Creates an empty result for the validation of a sub-schema
+func (v *Result) newSubResult() *Result {
+	return &Result{options: v.options, coverage: v.coverage, evaluated: v.evaluated, applied: v.applied, profile: v.profile, changes: v.changes, deadline: v.deadline, overrides: v.overrides}
+}
+
+// Sets the title of the errors about the node at context, starting from
+// the error at index from, replacing the title given by the subSchemas nested
+// at the same node. Nothing is done when the contexts are not tracked.
+func (v *Result) setErrorsTitle(title string, context *JSONContext, from int) {
+	if context == nil {
+		return
+	}
+	for i := from; i < len(v.errors); i++ {
+		if v.errors[i].Context == context {
+			v.errors[i].Title = title
+		}
+	}
+}
+
+// Tells whether the validation of a child node, by key or index, is skipped
+// as the node did not change, see Schema.ValidatePatched
+func (v *Result) skips(key string, context *JSONContext) bool {
+	return v.changes != nil && !v.changes.touches(v.newContext(key, context))
+}
+
+// Context of a child node, nil when paths are not tracked (ValidateOptions.IsValid)
+func (v *Result) newContext(head string, tail *JSONContext) *JSONContext {
+	if v.options.IsValid {
+		return nil
+	}
+	return NewJSONContext(head, tail)
+}
+
+// Adds an annotation, which does not affect the validity nor the score
+func (v *Result) addAnnotation(
+	context *JSONContext,
+	reason string,
+	requirement interface{},
+	value interface{},
+) {
+	v.annotations = append(v.annotations, ResultError{
+		Context:     context,
+		Reason:      reason,
+		Requirement: requirement,
+		Value:       value,
+	})
 }
 
 // Used to copy errors from a sub-schema to the main one
 func (v *Result) mergeErrors(otherResult *Result) {
+	v.annotations = append(v.annotations, otherResult.annotations...)
 	v.errors = append(v.errors, otherResult.Errors()...)
 	v.score += otherResult.score
 }
 
+// Tells whether the scores are kept, see ValidateOptions.unscored
+func (v *Result) scored() bool {
+	return v.options == nil || !v.options.unscored
+}
+
 func (v *Result) incrementScore() {
-	v.score++
+	if v.scored() {
+		v.score++
+	}
+}
+
+// Weights the score of a property once validated : a matching property earns
+// a bonus, a failing one never costs more than a single error however many
+// errors its value holds, so partially matching objects are not outranked
+// by subSchemas that barely looked at them.
+func (v *Result) scoreProperty(scoreBefore int, nbErrorsBefore int) {
+	if !v.scored() {
+		return
+	}
+	if len(v.errors) == nbErrorsBefore {
+		v.score++
+	} else if v.score < scoreBefore-2 {
+		v.score = scoreBefore - 2
+	}
 }
 
 // ResultErrors is a collection of JSON schema errors
@@ -118,6 +515,122 @@ func (rerrs ResultErrors) Error() string {
 	return fmt.Sprintf("%d fields with validation error(s)", len(rerrs))
 }
 
+// Dedup returns the errors without repetitions, keeping the first occurrence of
+// each one. Two errors are the same when they have the same context, reason,
+// requirement and value, the details are not compared.
+func (rerrs ResultErrors) Dedup() ResultErrors {
+
+	type identity struct {
+		context, reason, requirement, value string
+	}
+
+	seen := make(map[identity]bool, len(rerrs))
+	var deduped ResultErrors
+	for _, rerr := range rerrs {
+		id := identity{
+			context:     rerr.Context.String(),
+			reason:      rerr.Reason,
+			requirement: resultErrorIdentityString(rerr.Requirement),
+			value:       resultErrorIdentityString(rerr.Value),
+		}
+		if !seen[id] {
+			seen[id] = true
+			deduped = append(deduped, rerr)
+		}
+	}
+
+	return deduped
+}
+
+// Keeps the first max errors of each context
+func (rerrs ResultErrors) limitPerContext(max int) ResultErrors {
+
+	counts := make(map[string]int)
+	var limited ResultErrors
+	for _, rerr := range rerrs {
+		context := rerr.Context.String()
+		if counts[context] < max {
+			counts[context]++
+			limited = append(limited, rerr)
+		}
+	}
+
+	return limited
+}
+
+// Replaces the objects and arrays held by the errors by an ElidedValue. The
+// values are found in the document by identity, as the path of an error is not
+// always the one of its value, ex for dependencies. Values that are not part of
+// the document, like those converted while validating, are kept.
+func (rerrs ResultErrors) elideValues(root interface{}) {
+
+	var pointers map[nodeIdentity]string
+	for i := range rerrs {
+		id, ok := identityOf(rerrs[i].Value)
+		if !ok {
+			continue
+		}
+		if pointers == nil {
+			pointers = make(map[nodeIdentity]string)
+			collectPointers(root, "", pointers)
+		}
+		if pointer, ok := pointers[id]; ok {
+			elided := ElidedValue{Pointer: pointer, Type: TYPE_ARRAY, Length: id.length}
+			if id.kind == reflect.Map {
+				elided.Type = TYPE_OBJECT
+			}
+			rerrs[i].Value = elided
+		}
+	}
+}
+
+// Identifies the non empty objects and arrays of a document
+type nodeIdentity struct {
+	kind    reflect.Kind
+	pointer uintptr
+	length  int
+}
+
+func identityOf(value interface{}) (nodeIdentity, bool) {
+	rValue := reflect.ValueOf(value)
+	switch rValue.Kind() {
+	case reflect.Map, reflect.Slice:
+		if rValue.Len() > 0 {
+			return nodeIdentity{kind: rValue.Kind(), pointer: rValue.Pointer(), length: rValue.Len()}, true
+		}
+	}
+	return nodeIdentity{}, false
+}
+
+// Records the JSON pointer of each object and array of the document
+func collectPointers(node interface{}, pointer string, pointers map[nodeIdentity]string) {
+
+	if id, ok := identityOf(node); ok {
+		if _, seen := pointers[id]; !seen {
+			pointers[id] = pointer
+		}
+	}
+
+	switch node := node.(type) {
+	case map[string]interface{}:
+		for key, value := range node {
+			collectPointers(value, pointer+"/"+escapeJsonPointerToken(key), pointers)
+		}
+	case []interface{}:
+		for i, value := range node {
+			collectPointers(value, pointer+"/"+strconv.Itoa(i), pointers)
+		}
+	}
+}
+
+func resultErrorIdentityString(i interface{}) string {
+	s, err := marshalToJsonString(i)
+	if err != nil {
+		return fmt.Sprintf("%#v", i)
+	}
+	return *s
+}
+
 // Map parses ResultErrors into a map object for simpler error parsing/handling
 func (rerrs ResultErrors) Map() map[string][]interface{} {
 	var jmap = make(map[string][]interface{})
@@ -132,6 +645,80 @@ func (rerrs ResultErrors) Map() map[string][]interface{} {
 	return jmap
 }
 
+// Report formats the errors for humans, sorted by path, each one followed by
+// the value that failed, as JSON :
+//
+//	#/age: minimum,18
+//	    value: 16
+//	#/name: required
+//	    value: undefined
+func (rerrs ResultErrors) Report() string {
+
+	sorted := make(ResultErrors, len(rerrs))
+	copy(sorted, rerrs)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return sorted[i].Context.String() < sorted[j].Context.String()
+	})
+
+	var report bytes.Buffer
+	for _, rerr := range sorted {
+		value := STRING_UNDEFINED
+		if rerr.Value != emptyProperty {
+			value = resultErrorIdentityString(rerr.Value)
+		}
+		fmt.Fprintf(&report, "%s\n    %s: %s\n", rerr.String(), STRING_VALUE, value)
+	}
+
+	return report.String()
+}
+
+// FirstErrorKeywords orders the keywords whose errors ResultErrors.First
+// prefers among those of a same node, a wrong type or a missing property
+// telling more than a wrong length. The keywords not listed come after.
+var FirstErrorKeywords = []string{
+	KEY_TYPE, KEY_REQUIRED, KEY_ADDITIONAL_PROPERTIES, KEY_ADDITIONAL_ITEMS,
+	KEY_ENUM, KEY_ONE_OF, KEY_ANY_OF, KEY_FORMAT, KEY_PATTERN,
+}
+
+// First returns the most significant error : the one of the node closest to
+// the root, then of the keyword listed first in FirstErrorKeywords, then of
+// the first path in order. false when there are no errors.
+func (rerrs ResultErrors) First() (ResultError, bool) {
+
+	if len(rerrs) == 0 {
+		return ResultError{}, false
+	}
+
+	priority := func(rerr ResultError) int {
+		if i := indexOfString(FirstErrorKeywords, rerr.Reason); i >= 0 {
+			return i
+		}
+		return len(FirstErrorKeywords)
+	}
+
+	first := rerrs[0]
+	for _, rerr := range rerrs[1:] {
+		depth, firstDepth := len(rerr.Context.Segments()), len(first.Context.Segments())
+		if depth != firstDepth {
+			if depth < firstDepth {
+				first = rerr
+			}
+			continue
+		}
+		if p, firstP := priority(rerr), priority(first); p != firstP {
+			if p < firstP {
+				first = rerr
+			}
+			continue
+		}
+		if rerr.Context.String() < first.Context.String() {
+			first = rerr
+		}
+	}
+
+	return first, true
+}
+
 func (rerrs ResultErrors) MarshalJSON() ([]byte, error) {
 	return json.Marshal(rerrs.Map())
 }
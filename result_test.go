@@ -0,0 +1,284 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for Result and ResultError.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBestResultPrefersClosestBranch(t *testing.T) {
+
+	branchA := `{
+		"type": "object",
+		"properties": {
+			"kind": {"enum": ["a"]},
+			"a1": {"type": "string"},
+			"a2": {"type": "string"},
+			"a3": {"type": "string"},
+			"a4": {"type": "object", "required": ["b1", "b2", "b3", "b4", "b5", "b6", "b7", "b8", "b9", "b10", "b11", "b12", "b13", "b14", "b15", "b16"]}
+		},
+		"required": ["kind", "a1", "a2", "a3", "a4"]
+	}`
+	branchB := `{
+		"type": "object",
+		"properties": {"kind": {"enum": ["b"]}},
+		"required": ["kind"]
+	}`
+
+	// matches 4 of the 5 properties of branch A
+	document := NewStringLoader(`{"kind": "a", "a1": "x", "a2": "x", "a3": "x", "a4": {}}`)
+
+	for _, keyword := range []string{KEY_ONE_OF, KEY_ANY_OF} {
+		result, err := Validate(NewStringLoader(`{"`+keyword+`": [`+branchB+`, `+branchA+`]}`), document)
+		assert.Nil(t, err)
+		if assert.Len(t, result.Errors(), 16, keyword) {
+			for _, resultError := range result.Errors() {
+				assert.Equal(t, KEY_REQUIRED, resultError.Reason, keyword)
+			}
+		}
+	}
+}
+
+func TestBestResultTies(t *testing.T) {
+
+	single := &Result{score: -2}
+	assert.Equal(t, single, getBestResult([]*Result{single}))
+
+	fewerErrors := &Result{score: 1, errors: []ResultError{{}}}
+	moreErrors := &Result{score: 1, errors: []ResultError{{}, {}}}
+	assert.Equal(t, fewerErrors, getBestResult([]*Result{moreErrors, fewerErrors}))
+
+	assert.Nil(t, getBestResult([]*Result{{score: 1}, {score: 1}}))
+
+	// same choices without the list of results
+	var best bestResult
+	assert.Nil(t, best.get())
+	best.add(single)
+	assert.Equal(t, single, best.get())
+	best.add(moreErrors)
+	best.add(fewerErrors)
+	assert.Equal(t, fewerErrors, best.get())
+	best.add(&Result{score: 1, errors: []ResultError{{}}})
+	assert.Nil(t, best.get())
+	best.add(&Result{score: -5})
+	assert.Nil(t, best.get())
+	best.add(&Result{score: 2})
+	assert.Equal(t, 2, best.get().score)
+}
+
+func TestResultErrorsDedup(t *testing.T) {
+
+	context := NewJSONContext("a", NewJSONContext(STRING_CONTEXT_ROOT, nil))
+	errs := ResultErrors{
+		{Context: context, Reason: KEY_TYPE, Requirement: "string", Value: map[string]interface{}{"x": 1.0}},
+		{Context: NewJSONContext("a", NewJSONContext(STRING_CONTEXT_ROOT, nil)), Reason: KEY_TYPE, Requirement: "string", Value: map[string]interface{}{"x": 1}},
+		{Context: context, Reason: KEY_TYPE, Requirement: "string", Value: map[string]interface{}{"x": 2}},
+		{Context: context, Reason: KEY_ENUM, Requirement: "string", Value: map[string]interface{}{"x": 1}},
+		{Context: NewJSONContext("b", nil), Reason: KEY_TYPE, Requirement: "string", Value: map[string]interface{}{"x": 1}},
+	}
+
+	deduped := errs.Dedup()
+	if assert.Len(t, deduped, 4) {
+		assert.Equal(t, errs[0], deduped[0])
+		assert.Equal(t, errs[2:], deduped[1:])
+	}
+
+	// the same type applied three times to a property is reported once
+	result, err := Validate(
+		NewStringLoader(`{"properties": {"a": {"allOf": [{"type": "string"}, {"type": "string"}]}}, "allOf": [{"properties": {"a": {"type": "string"}}}]}`),
+		NewStringLoader(`{"a": 1}`),
+	)
+	assert.Nil(t, err)
+	typeErrors := 0
+	for _, resultError := range result.Errors() {
+		if resultError.Reason == KEY_TYPE {
+			typeErrors++
+		}
+	}
+	assert.Equal(t, 1, typeErrors)
+}
+
+func TestElideValues(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"a/b": {"items": {"maxProperties": 1}},
+			"c": {"type": "string"},
+			"d": {"maxItems": 0}
+		},
+		"dependencies": {"c": ["e"]}
+	}`))
+	assert.Nil(t, err)
+
+	var document interface{}
+	err = json.Unmarshal([]byte(`{"a/b": [{"x": 1, "y": 2}], "c": 1, "d": []}`), &document)
+	assert.Nil(t, err)
+
+	result, err := schema.ValidateWithOptions(NewGoLoader(document), ValidateOptions{ElideValues: true})
+	assert.Nil(t, err)
+
+	values := make(map[string]interface{})
+	for _, rerr := range result.Errors() {
+		values[rerr.Reason] = rerr.Value
+	}
+	assert.Equal(t, map[string]interface{}{
+		KEY_MAX_PROPERTIES: ElidedValue{Pointer: "/a~1b/0", Type: TYPE_OBJECT, Length: 2},
+		KEY_TYPE:           float64(1),
+		KEY_DEPENDENCIES:   ElidedValue{Pointer: "", Type: TYPE_OBJECT, Length: 3},
+	}, values)
+
+	for _, rerr := range result.Errors() {
+		if rerr.Reason == KEY_MAX_PROPERTIES {
+			assert.Equal(t, map[string]interface{}{"x": float64(1), "y": float64(2)}, rerr.ResolveValue(document))
+		}
+		if rerr.Reason == KEY_DEPENDENCIES {
+			assert.Equal(t, document, rerr.ResolveValue(document))
+		}
+	}
+}
+
+func TestResultErr(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"required": ["a"], "properties": {"b": {"type": "string"}}}`))
+	assert.Nil(t, err)
+
+	result, err := schema.Validate(NewStringLoader(`{"a": 1}`))
+	assert.Nil(t, err)
+	assert.Nil(t, result.Err())
+
+	result, err = schema.Validate(NewStringLoader(`{"b": 1}`))
+	assert.Nil(t, err)
+	err = result.Err()
+	if assert.NotNil(t, err) {
+		assert.Equal(t, "2 fields with validation error(s)", err.Error())
+
+		var rerrs ResultErrors
+		wrapped := fmt.Errorf("invalid request: %w", err)
+		if assert.True(t, errors.As(wrapped, &rerrs)) {
+			assert.Equal(t, result.Errors(), rerrs)
+		}
+	}
+}
+
+func TestResultErrorsReport(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"required": ["name"],
+		"properties": {"age": {"minimum": 18}, "tags": {"items": {"type": "string"}}}
+	}`))
+	assert.Nil(t, err)
+
+	result, err := schema.Validate(NewStringLoader(`{"tags": ["a", {"b": 1}], "age": 16}`))
+	assert.Nil(t, err)
+
+	assert.Equal(t, `#/age: minimum,18
+    value: "16"
+#/name: required
+    value: undefined
+#/tags/1: type,string
+    value: {"b":1}
+`, result.Errors().Report())
+
+	assert.Equal(t, "", ResultErrors{}.Report())
+}
+
+func TestResultErrorsFirst(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"required": ["name"],
+		"properties": {
+			"age": {"minimum": 18},
+			"tags": {"items": {"type": "string"}},
+			"a": {"type": "string"},
+			"b": {"type": "string"}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	for document, expected := range map[string]string{
+		// the closest to the root, then by keyword, then by path
+		`{"tags": [1], "age": 16}`:              "#/name: required",
+		`{"name": "n", "tags": [1], "age": 16}`: "#/age: minimum,18",
+		`{"name": "n", "b": 1, "a": 2}`:         "#/a: type,string",
+		`{"name": "n"}`:                         "",
+	} {
+		message, err := schema.ValidateFirstError(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, expected, message, document)
+	}
+
+	_, err = schema.ValidateFirstError(NewStringLoader(`{`))
+	assert.NotNil(t, err)
+
+	// the priority of the keywords can be changed
+	defer func(keywords []string) { FirstErrorKeywords = keywords }(FirstErrorKeywords)
+	FirstErrorKeywords = []string{KEY_MINIMUM}
+	message, err := schema.ValidateFirstError(NewStringLoader(`{"age": 16}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "#/age: minimum,18", message)
+
+	_, ok := ResultErrors{}.First()
+	assert.False(t, ok)
+}
+
+func TestStructuredErrors(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"type": "object",
+		"required": ["name"],
+		"properties": {"age": {"minimum": 18}, "tags": {"items": {"type": "string"}}, "a/b": {"enum": [1]}}
+	}`))
+	assert.Nil(t, err)
+
+	result, err := schema.Validate(NewStringLoader(`{"tags": ["a", {"b": 1}], "age": 16, "a/b": 2}`))
+	assert.Nil(t, err)
+
+	structured := result.Errors().Structured()
+	sort.Slice(structured, func(i, j int) bool { return structured[i].Path < structured[j].Path })
+	assert.Equal(t, []StructuredError{
+		// the values of the numeric keywords are formatted numbers
+		{Path: "/age", Keyword: KEY_MINIMUM, Message: "minimum,18", Params: map[string]string{STRING_REQUIREMENT: "18", STRING_VALUE: `"16"`}},
+		{Path: "/a~1b", Keyword: KEY_ENUM, Message: "enum,[1]", Params: map[string]string{STRING_REQUIREMENT: `["1"]`, STRING_VALUE: "2"}},
+		{Path: "/name", Keyword: KEY_REQUIRED, Message: "required", Params: map[string]string{}},
+		{Path: "/tags/1", Keyword: KEY_TYPE, Message: "type,string", Params: map[string]string{
+			STRING_REQUIREMENT: `"string"`, STRING_VALUE: `{"b":1}`, STRING_EXPECTED: `["string"]`, STRING_ACTUAL: `"object"`,
+		}},
+	}, structured)
+
+	result, err = schema.Validate(NewStringLoader(`[]`))
+	assert.Nil(t, err)
+	if structured := result.Errors().Structured(); assert.Len(t, structured, 1) {
+		assert.Equal(t, "", structured[0].Path)
+	}
+
+	assert.Equal(t, []StructuredError{}, ResultErrors{}.Structured())
+}
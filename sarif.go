@@ -0,0 +1,144 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      Errors of a validation as a SARIF log, for code review tools.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"encoding/json"
+	"sort"
+)
+
+const (
+	SARIF_VERSION   = "2.1.0"
+	SARIF_SCHEMA    = "https://json.schemastore.org/sarif-2.1.0.json"
+	SARIF_TOOL_NAME = "gojsonschema"
+)
+
+// The parts of SARIF 2.1.0 used to report the errors
+type sarifLog struct {
+	Version string     `json:"version"`
+	Schema  string     `json:"$schema"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver sarifDriver `json:"driver"`
+}
+
+type sarifDriver struct {
+	Name  string      `json:"name"`
+	Rules []sarifRule `json:"rules"`
+}
+
+type sarifRule struct {
+	ID string `json:"id"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation sarifPhysicalLocation  `json:"physicalLocation"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+	Region           *sarifRegion          `json:"region,omitempty"`
+}
+
+type sarifArtifactLocation struct {
+	URI string `json:"uri"`
+}
+
+type sarifRegion struct {
+	StartLine   int `json:"startLine"`
+	StartColumn int `json:"startColumn"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// SARIF returns the errors as a SARIF 2.1.0 log, for the tools annotating code
+// reviews. Each error is a result whose rule is its keyword, located in the
+// document at sourceURI by its JSON pointer, and by its line and column when
+// the validation was started with the TrackPositions option. The results are
+// ordered by pointer, then by keyword, so that a document gives the same log.
+func (v *Result) SARIF(sourceURI string) ([]byte, error) {
+
+	run := sarifRun{
+		Tool:    sarifTool{Driver: sarifDriver{Name: SARIF_TOOL_NAME, Rules: []sarifRule{}}},
+		Results: []sarifResult{},
+	}
+
+	// the errors of the properties of an object come in no particular order
+	sorted := make(ResultErrors, len(v.errors))
+	copy(sorted, v.errors)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if pi, pj := sorted[i].Context.Pointer(), sorted[j].Context.Pointer(); pi != pj {
+			return pi < pj
+		}
+		return sorted[i].Reason < sorted[j].Reason
+	})
+
+	rules := make(map[string]bool)
+	for _, rerr := range sorted {
+		if !rules[rerr.Reason] {
+			rules[rerr.Reason] = true
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, sarifRule{ID: rerr.Reason})
+		}
+
+		location := sarifLocation{
+			PhysicalLocation: sarifPhysicalLocation{ArtifactLocation: sarifArtifactLocation{URI: sourceURI}},
+			LogicalLocations: []sarifLogicalLocation{{FullyQualifiedName: rerr.Context.Pointer()}},
+		}
+		if rerr.Line > 0 {
+			location.PhysicalLocation.Region = &sarifRegion{StartLine: rerr.Line, StartColumn: rerr.Column}
+		}
+
+		run.Results = append(run.Results, sarifResult{
+			RuleID:    rerr.Reason,
+			Level:     "error",
+			Message:   sarifMessage{Text: rerr.String()},
+			Locations: []sarifLocation{location},
+		})
+	}
+
+	return json.Marshal(sarifLog{Version: SARIF_VERSION, Schema: SARIF_SCHEMA, Runs: []sarifRun{run}})
+}
@@ -0,0 +1,78 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for the SARIF logs of the validations.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSARIF(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"properties": {"age": {"minimum": 18}, "tags": {"items": {"type": "string"}}}}`))
+	assert.Nil(t, err)
+
+	result, err := schema.ValidateWithOptions(NewStringLoader("{\n  \"age\": 16,\n  \"tags\": [1]\n}"), ValidateOptions{TrackPositions: true})
+	assert.Nil(t, err)
+
+	sarif, err := result.SARIF("testdata/person.json")
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"version": "2.1.0",
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"runs": [{
+			"tool": {"driver": {"name": "gojsonschema", "rules": [{"id": "minimum"}, {"id": "type"}]}},
+			"results": [
+				{
+					"ruleId": "minimum", "level": "error", "message": {"text": "#/age: minimum,18"},
+					"locations": [{
+						"physicalLocation": {"artifactLocation": {"uri": "testdata/person.json"}, "region": {"startLine": 2, "startColumn": 10}},
+						"logicalLocations": [{"fullyQualifiedName": "/age"}]
+					}]
+				},
+				{
+					"ruleId": "type", "level": "error", "message": {"text": "#/tags/0: type,string"},
+					"locations": [{
+						"physicalLocation": {"artifactLocation": {"uri": "testdata/person.json"}, "region": {"startLine": 3, "startColumn": 12}},
+						"logicalLocations": [{"fullyQualifiedName": "/tags/0"}]
+					}]
+				}
+			]
+		}]
+	}`, string(sarif))
+
+	// without positions, nor errors
+	result, err = schema.Validate(NewStringLoader(`{"age": 20}`))
+	assert.Nil(t, err)
+	sarif, err = result.SARIF("person.json")
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{
+		"version": "2.1.0",
+		"$schema": "https://json.schemastore.org/sarif-2.1.0.json",
+		"runs": [{"tool": {"driver": {"name": "gojsonschema", "rules": []}}, "results": []}]
+	}`, string(sarif))
+}
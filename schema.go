@@ -32,12 +32,18 @@ import (
 	"fmt"
 	"reflect"
 	"regexp"
+	"strconv"
+	"strings"
 
 	"github.com/xeipuuv/gojsonreference"
 )
 
 func NewSchema(l JSONLoader) (*Schema, error) {
-	return l.loadSchema()
+	return l.loadSchema(SchemaLoaderOptions{})
+}
+
+func NewSchemaWithOptions(l JSONLoader, options SchemaLoaderOptions) (*Schema, error) {
+	return l.loadSchema(options)
 }
 
 type Schema struct {
@@ -45,10 +51,59 @@ type Schema struct {
 	rootSchema        *subSchema
 	pool              *schemaPool
 	referencePool     *schemaReferencePool
+	options           SchemaLoaderOptions
+	warnings          []string
+	// Tells whether a subSchema has x-propertyOrder, which needs the order of
+	// the keys of the validated documents
+	propertyOrdered bool
+	// Tells whether a subSchema has x-maxDecimals, which needs the numbers of
+	// the validated documents as written
+	decimalsBounded bool
+	// Tells whether a subSchema has anyOf or oneOf, the only keywords to
+	// compare the scores of the results
+	combined bool
+}
+
+// SchemaLoaderOptions holds the settings used to parse a schema.
+type SchemaLoaderOptions struct {
+
+	// Parses the extension keywords, prefixed by "x-".
+	// They are ignored otherwise, as any unknown keyword.
+	EnableExtensions bool
+
+	// Checks the schema for likely authoring mistakes : an empty enum, which
+	// no value can match, is an error and an empty required is reported by
+	// Schema.Warnings.
+	StrictSchema bool
+
+	// Matches pattern against the whole string, as if it were written
+	// \A(?:pattern)\z. By the specification a pattern is unanchored : "b" is
+	// valid against "abc", which the option makes invalid. patternProperties
+	// and the format regex are left unanchored.
+	AnchorPatterns bool
+
+	// Custom keywords, by name. Each node of the document validated by a
+	// subSchema declaring one of these keywords is given to its KeywordValidator,
+	// see also KeywordCompiler. They take precedence over the keywords of
+	// RegisterKeyword. LoadCompiled only knows the registered keywords and
+	// fails on the others.
+	Keywords map[string]KeywordValidator
+
+	// Loads the documents of the $ref that point to other documents, given
+	// their URI without the fragment, ex "urn:example:address" or
+	// "schema://internal/address.json". It is called once per document,
+	// before the HTTP and file loaders, which are used when it returns nil.
+	// The document is a decoded JSON, or any value NewGoLoader accepts.
+	// An error aborts the construction of the schema.
+	RefResolver func(uri string) (interface{}, error)
+
+	// Resolves the $ref to the ids of the schemas it holds, before the
+	// RefResolver and the HTTP and file loaders.
+	Registry *SchemaRegistry
 }
 
 func (d *Schema) parse(document interface{}) error {
-	d.rootSchema = &subSchema{property: STRING_ROOT_SCHEMA_PROPERTY}
+	d.rootSchema = &subSchema{property: STRING_ROOT_SCHEMA_PROPERTY, location: documentLocation(d.documentReference)}
 	return d.parseSchema(document, d.rootSchema)
 }
 
@@ -56,6 +111,121 @@ func (d *Schema) SetRootSchemaName(name string) {
 	d.rootSchema.property = name
 }
 
+// References returns the sorted, resolved URIs of all the $ref of the schema,
+// including those found in the referenced documents. The references to the
+// schema's own document are included, ex #/definitions/a for a schema given
+// by a string loader.
+func (d *Schema) References() []string {
+
+	references := make(map[string]bool)
+	visited := make(map[*subSchema]bool)
+
+	var walk func(s *subSchema)
+	walk = func(s *subSchema) {
+		if visited[s] {
+			return
+		}
+		visited[s] = true
+		if s.refSchema != nil {
+			references[s.refSchema.location] = true
+		}
+		for _, child := range s.children() {
+			walk(child)
+		}
+	}
+	walk(d.rootSchema)
+
+	return sortedKeys(references)
+}
+
+// Warnings returns the remarks on the schema made by the StrictSchema option
+// that do not make it invalid, with the location of the subSchema concerned.
+func (d *Schema) Warnings() []string {
+	return d.warnings
+}
+
+// MetaSchema returns the $schema declared at the root of the schema, the URI
+// of the draft it targets, or an empty string. The URI is the one of the parsed
+// reference : an empty fragment, as in ".../draft-04/schema#", is dropped.
+func (d *Schema) MetaSchema() string {
+	if d.rootSchema.subSchema == nil {
+		return ""
+	}
+	return d.rootSchema.subSchema.String()
+}
+
+// ID returns the $id declared at the root of the schema, or an empty string.
+func (d *Schema) ID() string {
+	if d.rootSchema.id == nil {
+		return ""
+	}
+	return *d.rootSchema.id
+}
+
+// Tells whether a property is defined at the root of the schema, see subSchema.HasProperty
+func (d *Schema) HasProperty(name string) bool {
+	return d.rootSchema.HasProperty(name)
+}
+
+// EnumAt returns the members of the enum of the subSchema at a JSON pointer
+// of the schema, ex "/properties/status" or "#/properties/status", following
+// its $ref. It returns false when there is no subSchema at the pointer or when
+// it has no enum. The members are decoded JSON values : numbers are float64.
+func (d *Schema) EnumAt(pointer string) ([]interface{}, bool) {
+
+	s := d.subSchemaAt(pointer)
+	if s != nil {
+		s = s.resolvedRef()
+	}
+
+	if s == nil || s.enum == nil {
+		return nil, false
+	}
+	return s.enumValues(), true
+}
+
+// Returns the decoded JSON document the schema was parsed from, nil for the
+// compiled schemas
+func (d *Schema) document() interface{} {
+
+	if document := d.pool.GetStandaloneDocument(); document != nil {
+		return document
+	}
+	refToUrl := *d.documentReference.GetUrl()
+	refToUrl.Fragment = ""
+	if spd, ok := d.pool.schemaPoolDocuments[refToUrl.String()]; ok {
+		return spd.Document
+	}
+
+	return nil
+}
+
+// Returns the subSchema at a JSON pointer of the schema document, nil if none
+func (d *Schema) subSchemaAt(pointer string) *subSchema {
+
+	location := documentLocation(d.documentReference) + strings.TrimPrefix(pointer, "#")
+	visited := make(map[*subSchema]bool)
+
+	var walk func(s *subSchema) *subSchema
+	walk = func(s *subSchema) *subSchema {
+		if visited[s] {
+			return nil
+		}
+		visited[s] = true
+		if s.location == location {
+			return s
+		}
+		for _, child := range s.children() {
+			if found := walk(child); found != nil {
+				return found
+			}
+		}
+		return nil
+	}
+
+	return walk(d.rootSchema)
+}
+
 // Parses a subSchema
 //
 // Pretty long function ( sorry :) )... but pretty straight forward, repetitive and boring
@@ -74,7 +244,7 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 		currentSchema.ref = &d.documentReference
 	}
 
-	// $subSchema
+	// $schema
 	if existsMapKey(m, KEY_SCHEMA) {
 		if !isKind(m[KEY_SCHEMA], reflect.String) {
 			return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, KEY_SCHEMA, TYPE_STRING))
@@ -92,43 +262,54 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 		return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, KEY_REF, TYPE_STRING))
 	}
 	if k, ok := m[KEY_REF].(string); ok {
+		// the siblings of $ref, definitions included, are ignored :
+		// the referenced part of the document is parsed on its own
+		return d.parseReference(documentNode, currentSchema, k)
+	}
 
-		if sch, ok := d.referencePool.Get(currentSchema.ref.String() + k); ok {
-
-			currentSchema.refSchema = sch
-
-		} else {
-
-			var err error
-			err = d.parseReference(documentNode, currentSchema, k)
-			if err != nil {
-				return err
+	// $merge and $patch : the subSchema is the one their source becomes once
+	// patched, their siblings are ignored
+	if d.options.EnableExtensions && (existsMapKey(m, KEY_MERGE) || existsMapKey(m, KEY_PATCH)) {
+		composed, err := d.composeSchema(m, *currentSchema.ref, make(map[string]bool))
+		if err != nil {
+			keyword := KEY_MERGE
+			if !existsMapKey(m, KEY_MERGE) {
+				keyword = KEY_PATCH
 			}
-
-			return nil
+			return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_AT_Y_CANNOT_BE_APPLIED, keyword, currentSchema.location, err))
 		}
+		return d.parseSchema(composed, currentSchema)
 	}
 
-	// definitions
-	if existsMapKey(m, KEY_DEFINITIONS) {
-		if isKind(m[KEY_DEFINITIONS], reflect.Map) {
+	// $vocabulary, of the 2019-09 drafts, is accepted but has no effect
+	if existsMapKey(m, KEY_VOCABULARY) && !isKind(m[KEY_VOCABULARY], reflect.Map) {
+		return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, KEY_VOCABULARY, TYPE_OBJECT))
+	}
+
+	// definitions, and $defs as they are named since the 2019-09 drafts.
+	// The references lead to either by their JSON pointer. A name defined by
+	// both is kept for the one of definitions.
+	for _, keyword := range []string{KEY_DEFS, KEY_DEFINITIONS} {
+		if !existsMapKey(m, keyword) {
+			continue
+		}
+		if !isKind(m[keyword], reflect.Map) {
+			return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, keyword, STRING_ARRAY_OF_SCHEMAS))
+		}
+		if currentSchema.definitions == nil {
 			currentSchema.definitions = make(map[string]*subSchema)
-			for dk, dv := range m[KEY_DEFINITIONS].(map[string]interface{}) {
-				if isKind(dv, reflect.Map) {
-					newSchema := &subSchema{property: KEY_DEFINITIONS, parent: currentSchema, ref: currentSchema.ref}
-					currentSchema.definitions[dk] = newSchema
-					err := d.parseSchema(dv, newSchema)
-					if err != nil {
-						return errors.New(err.Error())
-					}
-				} else {
-					return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, KEY_DEFINITIONS, STRING_ARRAY_OF_SCHEMAS))
-				}
+		}
+		for dk, dv := range m[keyword].(map[string]interface{}) {
+			if !isKind(dv, reflect.Map) {
+				return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, keyword, STRING_ARRAY_OF_SCHEMAS))
+			}
+			newSchema := &subSchema{property: keyword, parent: currentSchema, ref: currentSchema.ref, location: currentSchema.childLocation(keyword, dk)}
+			currentSchema.definitions[dk] = newSchema
+			err := d.parseSchema(dv, newSchema)
+			if err != nil {
+				return errors.New(err.Error())
 			}
-		} else {
-			return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, KEY_DEFINITIONS, STRING_ARRAY_OF_SCHEMAS))
 		}
-
 	}
 
 	// id
@@ -155,6 +336,23 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 		currentSchema.description = &k
 	}
 
+	// $comment, for the authors of the schema, it does not affect validation
+	if existsMapKey(m, KEY_COMMENT) && !isKind(m[KEY_COMMENT], reflect.String) {
+		return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, KEY_COMMENT, TYPE_STRING))
+	}
+	if k, ok := m[KEY_COMMENT].(string); ok {
+		currentSchema.comment = &k
+	}
+
+	// default
+	if existsMapKey(m, KEY_DEFAULT) {
+		defaultValue, err := marshalToJsonString(m[KEY_DEFAULT])
+		if err != nil {
+			return err
+		}
+		currentSchema.defaultValue = defaultValue
+	}
+
 	// type
 	if existsMapKey(m, KEY_TYPE) {
 		if isKind(m[KEY_TYPE], reflect.String) {
@@ -194,7 +392,7 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 		if isKind(m[KEY_ADDITIONAL_PROPERTIES], reflect.Bool) {
 			currentSchema.additionalProperties = m[KEY_ADDITIONAL_PROPERTIES].(bool)
 		} else if isKind(m[KEY_ADDITIONAL_PROPERTIES], reflect.Map) {
-			newSchema := &subSchema{property: KEY_ADDITIONAL_PROPERTIES, parent: currentSchema, ref: currentSchema.ref}
+			newSchema := &subSchema{property: KEY_ADDITIONAL_PROPERTIES, parent: currentSchema, ref: currentSchema.ref, location: currentSchema.childLocation(KEY_ADDITIONAL_PROPERTIES)}
 			currentSchema.additionalProperties = newSchema
 			err := d.parseSchema(m[KEY_ADDITIONAL_PROPERTIES], newSchema)
 			if err != nil {
@@ -216,7 +414,7 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 					if err != nil {
 						return errors.New(fmt.Sprintf(ERROR_MESSAGE_INVALID_REGEX_PATTERN, k))
 					}
-					newSchema := &subSchema{property: k, parent: currentSchema, ref: currentSchema.ref}
+					newSchema := &subSchema{property: k, parent: currentSchema, ref: currentSchema.ref, location: currentSchema.childLocation(KEY_PATTERN_PROPERTIES, k)}
 					err = d.parseSchema(v, newSchema)
 					if err != nil {
 						return errors.New(err.Error())
@@ -237,12 +435,43 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 		}
 	}
 
+	// dependentSchemas
+	if existsMapKey(m, KEY_DEPENDENT_SCHEMAS) {
+		if isKind(m[KEY_DEPENDENT_SCHEMAS], reflect.Map) {
+			currentSchema.dependentSchemas = make(map[string]*subSchema)
+			for k, v := range m[KEY_DEPENDENT_SCHEMAS].(map[string]interface{}) {
+				newSchema := &subSchema{property: k, parent: currentSchema, ref: currentSchema.ref, location: currentSchema.childLocation(KEY_DEPENDENT_SCHEMAS, k)}
+				err := d.parseSchema(v, newSchema)
+				if err != nil {
+					return err
+				}
+				currentSchema.dependentSchemas[k] = newSchema
+			}
+		} else {
+			return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, KEY_DEPENDENT_SCHEMAS, TYPE_OBJECT))
+		}
+	}
+
+	// propertyNames
+	if existsMapKey(m, KEY_PROPERTY_NAMES) {
+		if isKind(m[KEY_PROPERTY_NAMES], reflect.Map) {
+			newSchema := &subSchema{property: KEY_PROPERTY_NAMES, parent: currentSchema, ref: currentSchema.ref, location: currentSchema.childLocation(KEY_PROPERTY_NAMES)}
+			currentSchema.propertyNames = newSchema
+			err := d.parseSchema(m[KEY_PROPERTY_NAMES], newSchema)
+			if err != nil {
+				return err
+			}
+		} else {
+			return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_AN_Y, KEY_PROPERTY_NAMES, TYPE_OBJECT))
+		}
+	}
+
 	// items
 	if existsMapKey(m, KEY_ITEMS) {
 		if isKind(m[KEY_ITEMS], reflect.Slice) {
-			for _, itemElement := range m[KEY_ITEMS].([]interface{}) {
+			for i, itemElement := range m[KEY_ITEMS].([]interface{}) {
 				if isKind(itemElement, reflect.Map) {
-					newSchema := &subSchema{parent: currentSchema, property: KEY_ITEMS}
+					newSchema := &subSchema{parent: currentSchema, property: KEY_ITEMS, location: currentSchema.childLocation(KEY_ITEMS, strconv.Itoa(i))}
 					newSchema.ref = currentSchema.ref
 					currentSchema.AddItemsChild(newSchema)
 					err := d.parseSchema(itemElement, newSchema)
@@ -255,7 +484,7 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 				currentSchema.itemsChildrenIsSingleSchema = false
 			}
 		} else if isKind(m[KEY_ITEMS], reflect.Map) {
-			newSchema := &subSchema{parent: currentSchema, property: KEY_ITEMS}
+			newSchema := &subSchema{parent: currentSchema, property: KEY_ITEMS, location: currentSchema.childLocation(KEY_ITEMS)}
 			newSchema.ref = currentSchema.ref
 			currentSchema.AddItemsChild(newSchema)
 			err := d.parseSchema(m[KEY_ITEMS], newSchema)
@@ -263,8 +492,11 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 				return err
 			}
 			currentSchema.itemsChildrenIsSingleSchema = true
+		} else if isKind(m[KEY_ITEMS], reflect.Bool) {
+			itemsBoolean := m[KEY_ITEMS].(bool)
+			currentSchema.itemsBoolean = &itemsBoolean
 		} else {
-			return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, KEY_ITEMS, STRING_SCHEMA+"/"+STRING_ARRAY_OF_SCHEMAS))
+			return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, KEY_ITEMS, TYPE_BOOLEAN+"/"+STRING_SCHEMA+"/"+STRING_ARRAY_OF_SCHEMAS))
 		}
 	}
 
@@ -273,7 +505,7 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 		if isKind(m[KEY_ADDITIONAL_ITEMS], reflect.Bool) {
 			currentSchema.additionalItems = m[KEY_ADDITIONAL_ITEMS].(bool)
 		} else if isKind(m[KEY_ADDITIONAL_ITEMS], reflect.Map) {
-			newSchema := &subSchema{property: KEY_ADDITIONAL_ITEMS, parent: currentSchema, ref: currentSchema.ref}
+			newSchema := &subSchema{property: KEY_ADDITIONAL_ITEMS, parent: currentSchema, ref: currentSchema.ref, location: currentSchema.childLocation(KEY_ADDITIONAL_ITEMS)}
 			currentSchema.additionalItems = newSchema
 			err := d.parseSchema(m[KEY_ADDITIONAL_ITEMS], newSchema)
 			if err != nil {
@@ -347,6 +579,26 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 		}
 	}
 
+	if d.options.EnableExtensions && existsMapKey(m, KEY_X_FORMAT) {
+		numberFormat, ok := m[KEY_X_FORMAT].(string)
+		if _, known := numberFormatRanges[numberFormat]; !ok || !known {
+			return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_A_Y, KEY_X_FORMAT, STRING_NUMBER_FORMAT))
+		}
+		currentSchema.numberFormat = &numberFormat
+	}
+
+	if d.options.EnableExtensions && existsMapKey(m, KEY_X_MAX_DECIMALS) {
+		maxDecimals := mustBeInteger(m[KEY_X_MAX_DECIMALS])
+		if maxDecimals == nil {
+			return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_AN_Y, KEY_X_MAX_DECIMALS, TYPE_INTEGER))
+		}
+		if *maxDecimals < 0 {
+			return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_GREATER_OR_TO_0, KEY_X_MAX_DECIMALS))
+		}
+		currentSchema.maxDecimals = maxDecimals
+		d.decimalsBounded = true
+	}
+
 	// validation : string
 
 	if existsMapKey(m, KEY_MIN_LENGTH) {
@@ -379,7 +631,21 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 
 	if existsMapKey(m, KEY_PATTERN) {
 		if isKind(m[KEY_PATTERN], reflect.String) {
-			regexpObject, err := regexp.Compile(m[KEY_PATTERN].(string))
+			pattern := m[KEY_PATTERN].(string)
+			if d.options.AnchorPatterns {
+				// \A and \z, unlike ^ and $, ignore the m flag
+				pattern = `\A(?:` + pattern + `)\z`
+			}
+			if d.options.EnableExtensions && existsMapKey(m, KEY_X_PATTERN_FLAGS) {
+				flags, ok := m[KEY_X_PATTERN_FLAGS].(string)
+				if !ok || strings.Trim(flags, PATTERN_FLAGS) != "" {
+					return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_A_Y, KEY_X_PATTERN_FLAGS, STRING_PATTERN_FLAGS))
+				}
+				if flags != "" {
+					pattern = "(?" + flags + ")" + pattern
+				}
+			}
+			regexpObject, err := regexp.Compile(pattern)
 			if err != nil {
 				return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_VALID_REGEX, KEY_PATTERN))
 			}
@@ -389,6 +655,45 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 		}
 	}
 
+	if existsMapKey(m, KEY_FORMAT) {
+		format, ok := m[KEY_FORMAT].(string)
+		if !ok {
+			return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, KEY_FORMAT, TYPE_STRING))
+		}
+		currentSchema.format = &format
+	}
+
+	if d.options.EnableExtensions && existsMapKey(m, KEY_X_ANY_FORMAT) {
+		formats, ok := m[KEY_X_ANY_FORMAT].([]interface{})
+		if !ok || len(formats) == 0 {
+			return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_AN_Y, KEY_X_ANY_FORMAT, STRING_ARRAY_OF_STRINGS))
+		}
+		currentSchema.anyFormat = make([]string, 0, len(formats))
+		for _, f := range formats {
+			format, ok := f.(string)
+			if !ok {
+				return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_ITEMS_MUST_BE_TYPE_Y, KEY_X_ANY_FORMAT, TYPE_STRING))
+			}
+			currentSchema.anyFormat = append(currentSchema.anyFormat, format)
+		}
+	}
+
+	if existsMapKey(m, KEY_CONTENT_ENCODING) {
+		contentEncoding, ok := m[KEY_CONTENT_ENCODING].(string)
+		if !ok {
+			return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, KEY_CONTENT_ENCODING, TYPE_STRING))
+		}
+		currentSchema.contentEncoding = &contentEncoding
+	}
+
+	if existsMapKey(m, KEY_CONTENT_MEDIA_TYPE) {
+		contentMediaType, ok := m[KEY_CONTENT_MEDIA_TYPE].(string)
+		if !ok {
+			return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, KEY_CONTENT_MEDIA_TYPE, TYPE_STRING))
+		}
+		currentSchema.contentMediaType = &contentMediaType
+	}
+
 	// validation : object
 
 	if existsMapKey(m, KEY_MIN_PROPERTIES) {
@@ -422,6 +727,9 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 	if existsMapKey(m, KEY_REQUIRED) {
 		if isKind(m[KEY_REQUIRED], reflect.Slice) {
 			requiredValues := m[KEY_REQUIRED].([]interface{})
+			if d.options.StrictSchema && len(requiredValues) == 0 {
+				d.warnings = append(d.warnings, fmt.Sprintf(ERROR_MESSAGE_X_IS_EMPTY_AT_Y, KEY_REQUIRED, currentSchema.location))
+			}
 			for _, requiredValue := range requiredValues {
 				if isKind(requiredValue, reflect.String) {
 					err := currentSchema.AddRequired(requiredValue.(string))
@@ -472,10 +780,83 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 		}
 	}
 
+	// custom keywords, in the order of their names so that the same error is
+	// returned for several invalid ones
+	for _, keyword := range sortedKeys(m) {
+		validator, ok := d.options.Keywords[keyword]
+		if !ok {
+			validator, ok = keywordValidators[keyword]
+		}
+		if ok {
+			if err := currentSchema.setKeyword(keyword, m[keyword], validator); err != nil {
+				return err
+			}
+		}
+	}
+
+	if d.options.EnableExtensions {
+		if existsMapKey(m, KEY_X_SORTED) {
+			sorted, ok := m[KEY_X_SORTED].(string)
+			if !ok || (sorted != SORTED_ASC && sorted != SORTED_DESC) {
+				return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_A_Y, KEY_X_SORTED, STRING_SORT_ORDER))
+			}
+			currentSchema.sorted = &sorted
+		}
+		if existsMapKey(m, KEY_X_SORTED_BY) {
+			if currentSchema.sorted == nil {
+				return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_CANNOT_BE_USED_WITHOUT_Y, KEY_X_SORTED_BY, KEY_X_SORTED))
+			}
+			sortedBy, ok := m[KEY_X_SORTED_BY].(string)
+			if !ok {
+				return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, KEY_X_SORTED_BY, TYPE_STRING))
+			}
+			currentSchema.sortedBy = &sortedBy
+		}
+		if existsMapKey(m, KEY_X_PROPERTY_ORDER) {
+			properties, ok := m[KEY_X_PROPERTY_ORDER].([]interface{})
+			if !ok {
+				return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_AN_Y, KEY_X_PROPERTY_ORDER, STRING_ARRAY_OF_STRINGS))
+			}
+			currentSchema.propertyOrder = make([]string, 0, len(properties))
+			for _, p := range properties {
+				property, ok := p.(string)
+				if !ok {
+					return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_ITEMS_MUST_BE_TYPE_Y, KEY_X_PROPERTY_ORDER, TYPE_STRING))
+				}
+				if isStringInSlice(currentSchema.propertyOrder, property) {
+					return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_ITEMS_MUST_BE_UNIQUE, KEY_X_PROPERTY_ORDER))
+				}
+				currentSchema.propertyOrder = append(currentSchema.propertyOrder, property)
+			}
+			d.propertyOrdered = true
+		}
+		if existsMapKey(m, KEY_X_ERROR_MESSAGE) {
+			switch message := m[KEY_X_ERROR_MESSAGE].(type) {
+			case string:
+				currentSchema.errorMessage = &message
+			case map[string]interface{}:
+				currentSchema.errorMessages = make(map[string]string, len(message))
+				for keyword, keywordMessage := range message {
+					s, ok := keywordMessage.(string)
+					if !ok {
+						return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_A_Y, KEY_X_ERROR_MESSAGE, STRING_STRING_OR_OBJECT))
+					}
+					currentSchema.errorMessages[keyword] = s
+				}
+			default:
+				return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_A_Y, KEY_X_ERROR_MESSAGE, STRING_STRING_OR_OBJECT))
+			}
+		}
+	}
+
 	// validation : all
 
 	if existsMapKey(m, KEY_ENUM) {
 		if isKind(m[KEY_ENUM], reflect.Slice) {
+			// nothing can match an empty enum
+			if d.options.StrictSchema && len(m[KEY_ENUM].([]interface{})) == 0 {
+				return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_IS_EMPTY_AT_Y, KEY_ENUM, currentSchema.location))
+			}
 			for _, v := range m[KEY_ENUM].([]interface{}) {
 				err := currentSchema.AddEnum(v)
 				if err != nil {
@@ -491,9 +872,10 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 
 	if existsMapKey(m, KEY_ONE_OF) {
 		if isKind(m[KEY_ONE_OF], reflect.Slice) {
-			for _, v := range m[KEY_ONE_OF].([]interface{}) {
-				newSchema := &subSchema{property: KEY_ONE_OF, parent: currentSchema, ref: currentSchema.ref}
+			for i, v := range m[KEY_ONE_OF].([]interface{}) {
+				newSchema := &subSchema{property: KEY_ONE_OF, parent: currentSchema, ref: currentSchema.ref, location: currentSchema.childLocation(KEY_ONE_OF, strconv.Itoa(i))}
 				currentSchema.AddOneOf(newSchema)
+				d.combined = true
 				err := d.parseSchema(v, newSchema)
 				if err != nil {
 					return err
@@ -504,11 +886,18 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 		}
 	}
 
+	if d.options.EnableExtensions && existsMapKey(m, KEY_X_DISCRIMINATOR) {
+		if err := d.parseDiscriminator(m[KEY_X_DISCRIMINATOR], currentSchema); err != nil {
+			return err
+		}
+	}
+
 	if existsMapKey(m, KEY_ANY_OF) {
 		if isKind(m[KEY_ANY_OF], reflect.Slice) {
-			for _, v := range m[KEY_ANY_OF].([]interface{}) {
-				newSchema := &subSchema{property: KEY_ANY_OF, parent: currentSchema, ref: currentSchema.ref}
+			for i, v := range m[KEY_ANY_OF].([]interface{}) {
+				newSchema := &subSchema{property: KEY_ANY_OF, parent: currentSchema, ref: currentSchema.ref, location: currentSchema.childLocation(KEY_ANY_OF, strconv.Itoa(i))}
 				currentSchema.AddAnyOf(newSchema)
+				d.combined = true
 				err := d.parseSchema(v, newSchema)
 				if err != nil {
 					return err
@@ -521,8 +910,8 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 
 	if existsMapKey(m, KEY_ALL_OF) {
 		if isKind(m[KEY_ALL_OF], reflect.Slice) {
-			for _, v := range m[KEY_ALL_OF].([]interface{}) {
-				newSchema := &subSchema{property: KEY_ALL_OF, parent: currentSchema, ref: currentSchema.ref}
+			for i, v := range m[KEY_ALL_OF].([]interface{}) {
+				newSchema := &subSchema{property: KEY_ALL_OF, parent: currentSchema, ref: currentSchema.ref, location: currentSchema.childLocation(KEY_ALL_OF, strconv.Itoa(i))}
 				currentSchema.AddAllOf(newSchema)
 				err := d.parseSchema(v, newSchema)
 				if err != nil {
@@ -536,7 +925,7 @@ func (d *Schema) parseSchema(documentNode interface{}, currentSchema *subSchema)
 
 	if existsMapKey(m, KEY_NOT) {
 		if isKind(m[KEY_NOT], reflect.Map) {
-			newSchema := &subSchema{property: KEY_NOT, parent: currentSchema, ref: currentSchema.ref}
+			newSchema := &subSchema{property: KEY_NOT, parent: currentSchema, ref: currentSchema.ref, location: currentSchema.childLocation(KEY_NOT)}
 			currentSchema.SetNot(newSchema)
 			err := d.parseSchema(m[KEY_NOT], newSchema)
 			if err != nil {
@@ -559,8 +948,6 @@ func (d *Schema) parseReference(documentNode interface{}, currentSchema *subSche
 		return err
 	}
 
-	standaloneDocument := d.pool.GetStandaloneDocument()
-
 	if jsonReference.HasFullUrl {
 		currentSchema.ref = &jsonReference
 	} else {
@@ -571,31 +958,16 @@ func (d *Schema) parseReference(documentNode interface{}, currentSchema *subSche
 		currentSchema.ref = inheritedReference
 	}
 
-	jsonPointer := currentSchema.ref.GetPointer()
-
-	var refdDocumentNode interface{}
-
-	if standaloneDocument != nil {
-
-		var err error
-		refdDocumentNode, _, err = jsonPointer.Get(standaloneDocument)
-		if err != nil {
-			return err
-		}
-
-	} else {
-
-		var err error
-		dsp, err := d.pool.GetDocument(*currentSchema.ref)
-		if err != nil {
-			return err
-		}
-
-		refdDocumentNode, _, err = jsonPointer.Get(dsp.Document)
-		if err != nil {
-			return err
-		}
+	// the pool is keyed by the resolved reference, so that "#" or
+	// "#/definitions/a" lead to the same subSchema wherever they appear
+	if sch, ok := d.referencePool.Get(currentSchema.ref.String()); ok {
+		currentSchema.refSchema = sch
+		return nil
+	}
 
+	refdDocumentNode, err := d.referencedNode(*currentSchema.ref)
+	if err != nil {
+		return err
 	}
 
 	if !isKind(refdDocumentNode, reflect.Map) {
@@ -605,8 +977,8 @@ func (d *Schema) parseReference(documentNode interface{}, currentSchema *subSche
 	// returns the loaded referenced subSchema for the caller to update its current subSchema
 	newSchemaDocument := refdDocumentNode.(map[string]interface{})
 
-	newSchema := &subSchema{property: KEY_REF, parent: currentSchema, ref: currentSchema.ref}
-	d.referencePool.Add(currentSchema.ref.String()+reference, newSchema)
+	newSchema := &subSchema{property: KEY_REF, parent: currentSchema, ref: currentSchema.ref, location: referenceLocation(*currentSchema.ref)}
+	d.referencePool.Add(currentSchema.ref.String(), newSchema)
 
 	err = d.parseSchema(newSchemaDocument, newSchema)
 	if err != nil {
@@ -619,6 +991,86 @@ func (d *Schema) parseReference(documentNode interface{}, currentSchema *subSche
 
 }
 
+// Returns the part of a document a resolved reference points to
+func (d *Schema) referencedNode(reference gojsonreference.JsonReference) (interface{}, error) {
+
+	jsonPointer := reference.GetPointer()
+
+	// a standalone document only holds the references to itself,
+	// the canonical ones ( full url or file path ) point to other documents
+	if standaloneDocument := d.pool.GetStandaloneDocument(); standaloneDocument != nil && !reference.IsCanonical() && !d.pool.IsResolvable(reference) {
+		node, _, err := jsonPointer.Get(standaloneDocument)
+		return node, err
+	}
+
+	dsp, err := d.pool.GetDocument(reference)
+	if err != nil {
+		return nil, err
+	}
+	node, _, err := jsonPointer.Get(dsp.Document)
+	return node, err
+}
+
+// Parses an x-discriminator, once the oneOf subSchemas it maps are parsed
+func (d *Schema) parseDiscriminator(documentNode interface{}, currentSchema *subSchema) error {
+
+	m, ok := documentNode.(map[string]interface{})
+	if !ok {
+		return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_AN_Y, KEY_X_DISCRIMINATOR, TYPE_OBJECT))
+	}
+	if len(currentSchema.oneOf) == 0 {
+		return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_CANNOT_BE_USED_WITHOUT_Y, KEY_X_DISCRIMINATOR, KEY_ONE_OF))
+	}
+
+	propertyName, ok := m[KEY_PROPERTY_NAME].(string)
+	if !ok {
+		return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, KEY_X_DISCRIMINATOR+" "+KEY_PROPERTY_NAME, TYPE_STRING))
+	}
+	currentSchema.discriminator = &discriminator{PropertyName: propertyName}
+
+	if !existsMapKey(m, KEY_MAPPING) {
+		return nil
+	}
+	mapping, ok := m[KEY_MAPPING].(map[string]interface{})
+	if !ok {
+		return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_AN_Y, KEY_X_DISCRIMINATOR+" "+KEY_MAPPING, TYPE_OBJECT))
+	}
+
+	currentSchema.discriminator.Mapping = make(map[string]string, len(mapping))
+	currentSchema.discriminator.Branches = make(map[string]int, len(mapping))
+	for value, r := range mapping {
+		reference, ok := r.(string)
+		if !ok {
+			return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_OF_TYPE_Y, KEY_X_DISCRIMINATOR+" "+KEY_MAPPING, STRING_OBJECT_OF_STRINGS))
+		}
+		jsonReference, err := gojsonreference.NewJsonReference(reference)
+		if err != nil {
+			return err
+		}
+		resolved, err := currentSchema.ref.Inherits(jsonReference)
+		if err != nil {
+			return err
+		}
+
+		// the branch having the same $ref
+		branch := -1
+		for i, oneOfSchema := range currentSchema.oneOf {
+			if oneOfSchema.refSchema != nil && referenceLocation(*oneOfSchema.ref) == referenceLocation(*resolved) {
+				branch = i
+				break
+			}
+		}
+		if branch < 0 {
+			return errors.New(fmt.Sprintf(ERROR_MESSAGE_REFERENCE_X_IS_NOT_IN_Y, reference, KEY_ONE_OF))
+		}
+
+		currentSchema.discriminator.Mapping[value] = reference
+		currentSchema.discriminator.Branches[value] = branch
+	}
+
+	return nil
+}
+
 func (d *Schema) parseProperties(documentNode interface{}, currentSchema *subSchema) error {
 
 	if !isKind(documentNode, reflect.Map) {
@@ -628,7 +1080,7 @@ func (d *Schema) parseProperties(documentNode interface{}, currentSchema *subSch
 	m := documentNode.(map[string]interface{})
 	for k := range m {
 		schemaProperty := k
-		newSchema := &subSchema{property: schemaProperty, parent: currentSchema, ref: currentSchema.ref}
+		newSchema := &subSchema{property: schemaProperty, parent: currentSchema, ref: currentSchema.ref, location: currentSchema.childLocation(KEY_PROPERTIES, k)}
 		currentSchema.AddPropertiesChild(newSchema)
 		err := d.parseSchema(m[k], newSchema)
 		if err != nil {
@@ -665,7 +1117,7 @@ func (d *Schema) parseDependencies(documentNode interface{}, currentSchema *subS
 			}
 
 		case reflect.Map:
-			depSchema := &subSchema{property: k, parent: currentSchema, ref: currentSchema.ref}
+			depSchema := &subSchema{property: k, parent: currentSchema, ref: currentSchema.ref, location: currentSchema.childLocation(KEY_DEPENDENCIES, k)}
 			err := d.parseSchema(m[k], depSchema)
 			if err != nil {
 				return err
@@ -40,6 +40,12 @@ type schemaPoolDocument struct {
 type schemaPool struct {
 	schemaPoolDocuments map[string]*schemaPoolDocument
 	standaloneDocument  interface{}
+	// see SchemaLoaderOptions.RefResolver
+	refResolver func(uri string) (interface{}, error)
+	// see SchemaLoaderOptions.Registry
+	registry *SchemaRegistry
+	// see LoaderOptions.HTTPCache
+	httpCache *HTTPCache
 }
 
 func newSchemaPool() *schemaPool {
@@ -59,20 +65,53 @@ func (p *schemaPool) GetStandaloneDocument() (document interface{}) {
 	return p.standaloneDocument
 }
 
+// Tells whether a reference points to another document than the standalone
+// one, which only the registry or the RefResolver can load when the reference
+// is not canonical
+func (p *schemaPool) IsResolvable(reference gojsonreference.JsonReference) bool {
+	refToUrl := *reference.GetUrl()
+	refToUrl.Fragment = ""
+	if refToUrl.String() == "" {
+		return false
+	}
+	if p.registry != nil {
+		if _, ok := p.registry.document(refToUrl.String()); ok {
+			return true
+		}
+	}
+	return p.refResolver != nil
+}
+
 func (p *schemaPool) GetDocument(reference gojsonreference.JsonReference) (*schemaPoolDocument, error) {
 
 	internalLog("Get Document ( %s )", reference.String())
 
 	var err error
 
+	// the url is copied, the reference shares it with the subSchemas
+	refToUrl := *reference.GetUrl()
+	refToUrl.Fragment = ""
+
+	if p.registry != nil && p.schemaPoolDocuments[refToUrl.String()] == nil {
+		if document, ok := p.registry.document(refToUrl.String()); ok {
+			spd := &schemaPoolDocument{Document: document}
+			p.schemaPoolDocuments[refToUrl.String()] = spd
+			return spd, nil
+		}
+	}
+
+	if p.refResolver != nil && p.schemaPoolDocuments[refToUrl.String()] == nil {
+		spd, err := p.resolve(refToUrl.String())
+		if err != nil || spd != nil {
+			return spd, err
+		}
+	}
+
 	// It is not possible to load anything that is not canonical...
 	if !reference.IsCanonical() {
-		return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_REFERENCE_X_MUST_BE_CANONICAL, reference))
+		return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_REFERENCE_X_MUST_BE_CANONICAL, reference.String()))
 	}
 
-	refToUrl := reference
-	refToUrl.GetUrl().Fragment = ""
-
 	var spd *schemaPoolDocument
 
 	// Try to find the requested document in the pool
@@ -87,7 +126,7 @@ func (p *schemaPool) GetDocument(reference gojsonreference.JsonReference) (*sche
 		return spd, nil
 	}
 
-	jsonReferenceLoader := NewReferenceLoader(reference.String())
+	jsonReferenceLoader := NewReferenceLoaderWithOptions(reference.String(), LoaderOptions{HTTPCache: p.httpCache})
 	document, err := jsonReferenceLoader.loadJSON()
 	if err != nil {
 		return nil, err
@@ -99,3 +138,27 @@ func (p *schemaPool) GetDocument(reference gojsonreference.JsonReference) (*sche
 
 	return spd, nil
 }
+
+// Loads a document with the RefResolver, nil when it leaves the document to
+// the HTTP and file loaders
+func (p *schemaPool) resolve(uri string) (*schemaPoolDocument, error) {
+
+	resolved, err := p.refResolver(uri)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_REFERENCE_X_CANNOT_BE_RESOLVED, uri, err.Error()))
+	}
+	if resolved == nil {
+		return nil, nil
+	}
+
+	// the document is normalized as those of NewGoLoader
+	document, err := NewGoLoader(resolved).loadJSON()
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_REFERENCE_X_CANNOT_BE_RESOLVED, uri, err.Error()))
+	}
+
+	spd := &schemaPoolDocument{Document: document}
+	p.schemaPoolDocuments[uri] = spd
+
+	return spd, nil
+}
@@ -0,0 +1,113 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      Registry of schemas referencing each other by their $id.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/xeipuuv/gojsonreference"
+)
+
+// SchemaRegistry holds parsed schemas by their $id. The $ref to these ids of
+// the schemas parsed with SchemaLoaderOptions.Registry are resolved from the
+// registry, without loading anything. A registry is safe for concurrent use.
+type SchemaRegistry struct {
+	lock    sync.RWMutex
+	schemas map[string]*Schema
+}
+
+func NewSchemaRegistry() *SchemaRegistry {
+	return &SchemaRegistry{schemas: make(map[string]*Schema)}
+}
+
+// Register adds a schema by its $id, replacing the one registered with the
+// same id. The schemas referenced by the registered ones are registered
+// first. The schemas without $id cannot be registered, nor the compiled ones
+// which do not keep their JSON document.
+func (r *SchemaRegistry) Register(schema *Schema) error {
+
+	id, err := registryKey(schema.ID())
+	if err != nil {
+		return err
+	}
+	if id == "" {
+		return errors.New(ERROR_MESSAGE_SCHEMA_HAS_NO_ID)
+	}
+	if schema.document() == nil {
+		return errors.New(fmt.Sprintf(ERROR_MESSAGE_SCHEMA_X_HAS_NO_DOCUMENT, id))
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.schemas[id] = schema
+
+	return nil
+}
+
+// Get returns the schema registered with an $id, false if there is none
+func (r *SchemaRegistry) Get(id string) (*Schema, bool) {
+
+	key, err := registryKey(id)
+	if err != nil {
+		return nil, false
+	}
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	schema, ok := r.schemas[key]
+
+	return schema, ok
+}
+
+// Returns the JSON document of the schema registered for the URI of a
+// document, without fragment
+func (r *SchemaRegistry) document(uri string) (interface{}, bool) {
+
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+	schema, ok := r.schemas[uri]
+	if !ok {
+		return nil, false
+	}
+
+	return schema.document(), true
+}
+
+// The ids are keyed without their fragment, as "urn:example:a#" names the
+// same document as "urn:example:a"
+func registryKey(id string) (string, error) {
+
+	reference, err := gojsonreference.NewJsonReference(id)
+	if err != nil {
+		return "", err
+	}
+	url := *reference.GetUrl()
+	url.Fragment = ""
+
+	return url.String(), nil
+}
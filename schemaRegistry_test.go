@@ -0,0 +1,86 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for the registry of schemas.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSchemaRegistry(t *testing.T) {
+
+	registry := NewSchemaRegistry()
+	options := SchemaLoaderOptions{Registry: registry}
+
+	zip, err := NewSchema(NewStringLoader(`{"$id": "urn:example:zip#", "type": "string", "pattern": "^[0-9]{5}$"}`))
+	assert.Nil(t, err)
+	assert.Nil(t, registry.Register(zip))
+
+	// resolved during the construction, from the registered schemas
+	address, err := NewSchemaWithOptions(NewStringLoader(`{
+		"$id": "https://example.com/address.json",
+		"properties": {"zip": {"$ref": "urn:example:zip"}},
+		"definitions": {"street": {"type": "string", "minLength": 1}}
+	}`), options)
+	assert.Nil(t, err)
+	assert.Nil(t, registry.Register(address))
+
+	person, err := NewSchemaWithOptions(NewStringLoader(`{
+		"properties": {
+			"home": {"$ref": "https://example.com/address.json"},
+			"street": {"$ref": "https://example.com/address.json#/definitions/street"}
+		}
+	}`), options)
+	assert.Nil(t, err)
+
+	result, err := person.Validate(NewStringLoader(`{"home": {"zip": "750"}, "street": ""}`))
+	assert.Nil(t, err)
+	var contexts []string
+	for _, resultError := range result.Errors() {
+		contexts = append(contexts, resultError.Context.String())
+	}
+	assert.ElementsMatch(t, []string{"#/home/zip", "#/street"}, contexts)
+
+	registered, ok := registry.Get("urn:example:zip")
+	assert.True(t, ok)
+	assert.Equal(t, zip, registered)
+	registered, ok = registry.Get("https://example.com/address.json#")
+	assert.True(t, ok)
+	assert.Equal(t, address, registered)
+	_, ok = registry.Get("urn:example:unknown")
+	assert.False(t, ok)
+
+	anonymous, err := NewSchema(NewStringLoader(`{"type": "string"}`))
+	assert.Nil(t, err)
+	assert.EqualError(t, registry.Register(anonymous), "Schema has no $id")
+
+	data, err := zip.MarshalCompiled()
+	assert.Nil(t, err)
+	compiled, err := LoadCompiled(data)
+	assert.Nil(t, err)
+	assert.EqualError(t, registry.Register(compiled), "Schema urn:example:zip has no JSON document")
+}
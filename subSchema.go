@@ -27,20 +27,25 @@
 package gojsonschema
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/xeipuuv/gojsonreference"
 )
 
 const (
-	KEY_SCHEMA                = "$subSchema"
+	KEY_SCHEMA                = "$schema"
 	KEY_ID                    = "$id"
 	KEY_REF                   = "$ref"
 	KEY_TITLE                 = "title"
 	KEY_DESCRIPTION           = "description"
+	KEY_COMMENT               = "$comment"
+	KEY_DEFAULT               = "default"
 	KEY_TYPE                  = "type"
 	KEY_ITEMS                 = "items"
 	KEY_ADDITIONAL_ITEMS      = "additionalItems"
@@ -48,6 +53,8 @@ const (
 	KEY_PATTERN_PROPERTIES    = "patternProperties"
 	KEY_ADDITIONAL_PROPERTIES = "additionalProperties"
 	KEY_DEFINITIONS           = "definitions"
+	KEY_DEFS                  = "$defs"
+	KEY_VOCABULARY            = "$vocabulary"
 	KEY_MULTIPLE_OF           = "multipleOf"
 	KEY_MINIMUM               = "minimum"
 	KEY_MAXIMUM               = "maximum"
@@ -56,9 +63,14 @@ const (
 	KEY_MIN_LENGTH            = "minLength"
 	KEY_MAX_LENGTH            = "maxLength"
 	KEY_PATTERN               = "pattern"
+	KEY_FORMAT                = "format"
+	KEY_CONTENT_ENCODING      = "contentEncoding"
+	KEY_CONTENT_MEDIA_TYPE    = "contentMediaType"
 	KEY_MIN_PROPERTIES        = "minProperties"
 	KEY_MAX_PROPERTIES        = "maxProperties"
 	KEY_DEPENDENCIES          = "dependencies"
+	KEY_DEPENDENT_SCHEMAS     = "dependentSchemas"
+	KEY_PROPERTY_NAMES        = "propertyNames"
 	KEY_REQUIRED              = "required"
 	KEY_MIN_ITEMS             = "minItems"
 	KEY_MAX_ITEMS             = "maxItems"
@@ -68,6 +80,84 @@ const (
 	KEY_ANY_OF                = "anyOf"
 	KEY_ALL_OF                = "allOf"
 	KEY_NOT                   = "not"
+
+	// extensions, parsed when SchemaLoaderOptions.EnableExtensions is set
+	KEY_X_PATTERN_FLAGS  = "x-patternFlags"
+	KEY_X_SORTED         = "x-sorted"
+	KEY_X_SORTED_BY      = "x-sortedBy"
+	KEY_X_FORMAT         = "x-format"
+	KEY_X_ANY_FORMAT     = "x-anyFormat"
+	KEY_X_MAX_DECIMALS   = "x-maxDecimals"
+	KEY_X_DISCRIMINATOR  = "x-discriminator"
+	KEY_X_PROPERTY_ORDER = "x-propertyOrder"
+	KEY_X_ERROR_MESSAGE  = "x-errorMessage"
+	KEY_MERGE            = "$merge"
+	KEY_PATCH            = "$patch"
+
+	// members of x-discriminator
+	KEY_PROPERTY_NAME = "propertyName"
+	KEY_MAPPING       = "mapping"
+
+	// members of $merge and $patch
+	KEY_SOURCE = "source"
+	KEY_WITH   = "with"
+)
+
+// Flags accepted by x-patternFlags, as understood by the regexp package:
+// i case-insensitive, m multi-line, s let . match \n, U ungreedy
+const PATTERN_FLAGS = "imsU"
+
+// Values of contentEncoding and contentMediaType that are checked
+const (
+	CONTENT_ENCODING_BASE64 = "base64"
+	CONTENT_MEDIA_TYPE_JSON = "application/json"
+)
+
+// Integer widths accepted by x-format
+const (
+	NUMBER_FORMAT_INT32 = "int32"
+	NUMBER_FORMAT_INT64 = "int64"
+)
+
+// Bounds of the values of each x-format, included
+var numberFormatRanges = map[string][2]float64{
+	NUMBER_FORMAT_INT32: {math.MinInt32, math.MaxInt32},
+	// 1<<63 - 1 is not a float64, the nearest one is 1<<63 which overflows
+	NUMBER_FORMAT_INT64: {math.MinInt64, math.Nextafter(1<<63, 0)},
+}
+
+// The x-discriminator of a subSchema : the values of the property mapped to
+// references, and to the oneOf subSchemas having these references
+type discriminator struct {
+	PropertyName string
+	Mapping      map[string]string `json:",omitempty"`
+	Branches     map[string]int    `json:",omitempty"`
+}
+
+// The oneOf subSchema the discriminator selects for a node, if any
+func (s *subSchema) discriminatedBranch(node interface{}) *subSchema {
+
+	if s.discriminator == nil {
+		return nil
+	}
+	object, ok := node.(map[string]interface{})
+	if !ok {
+		return nil
+	}
+	value, ok := object[s.discriminator.PropertyName].(string)
+	if !ok {
+		return nil
+	}
+	if branch, ok := s.discriminator.Branches[value]; ok {
+		return s.oneOf[branch]
+	}
+	return nil
+}
+
+// Orders accepted by x-sorted
+const (
+	SORTED_ASC  = "asc"
+	SORTED_DESC = "desc"
 )
 
 type subSchema struct {
@@ -76,9 +166,22 @@ type subSchema struct {
 	id          *string
 	title       *string
 	description *string
+	comment     *string
+
+	// messages of the errors at the node ( x-errorMessage ), for all the
+	// keywords or by keyword
+	errorMessage  *string
+	errorMessages map[string]string
+
+	// default value, stored as a JSON string
+	defaultValue *string
 
 	property string
 
+	// Location of the subSchema, a JSON reference to its document followed by
+	// the JSON pointer to the subSchema in this document. ex #/properties/a
+	location string
+
 	// Types associated with the subSchema
 	types jsonSchemaType
 
@@ -104,10 +207,23 @@ type subSchema struct {
 	minimum          *float64
 	exclusiveMinimum *bool
 
+	// integer width ( x-format ) the numbers must fit
+	numberFormat *string
+
+	// most digits ( x-maxDecimals ) the numbers may have after the decimal point
+	maxDecimals *int
+
 	// validation : string
 	minLength *int
 	maxLength *int
 	pattern   *regexp.Regexp
+	format    *string
+
+	// formats ( x-anyFormat ) one of which the strings must match
+	anyFormat []string
+
+	contentEncoding  *string
+	contentMediaType *string
 
 	// validation : object
 	minProperties *int
@@ -115,24 +231,44 @@ type subSchema struct {
 	required      []string
 
 	dependencies         map[string]interface{}
+	dependentSchemas     map[string]*subSchema
+	propertyNames        *subSchema
 	additionalProperties interface{}
 	patternProperties    map[string]*subSchema
 
+	// order in which the properties must appear in the JSON text ( x-propertyOrder )
+	propertyOrder []string
+
 	// validation : array
 	minItems    *int
 	maxItems    *int
 	uniqueItems *bool
 
+	// items given as a boolean schema, false forbidding any item
+	itemsBoolean *bool
+
+	// order of the items ( x-sorted ), compared by one of their properties ( x-sortedBy )
+	sorted   *string
+	sortedBy *string
+
 	additionalItems interface{}
 
 	// validation : all
 	enum []string
 
+	// property whose value tells the oneOf subSchema to validate ( x-discriminator )
+	discriminator *discriminator
+
 	// validation : subSchema
 	oneOf []*subSchema
 	anyOf []*subSchema
 	allOf []*subSchema
 	not   *subSchema
+
+	// custom keywords declared by the subSchema, see KeywordValidator,
+	// and their value as found in the schema
+	keywords      map[string]KeywordValidator
+	keywordValues map[string]interface{}
 }
 
 func marshalSubSchemas(subschemaList []*subSchema) (subschemas []interface{}) {
@@ -143,110 +279,232 @@ func marshalSubSchemas(subschemaList []*subSchema) (subschemas []interface{}) {
 }
 
 // marshalSubSchema marshals a subschema into JSON
+// Every keyword held by the subschema is marshaled, regardless of its types,
+// so the result mirrors the source schema. Referenced schemas are marshaled as
+// their reference to avoid walking recursive definitions.
 func marshalSubSchema(s *subSchema) interface{} {
-	m := map[string]interface{}{
-		"type": s.types.String(),
+	m := map[string]interface{}{}
+
+	if s.refSchema != nil {
+		m[KEY_REF] = s.ref.String()
+		return m
 	}
 
-	if s.types.Contains(TYPE_OBJECT) {
-		if len(s.propertiesChildren) != 0 {
-			p := make(map[string]interface{})
-			for _, ss := range s.propertiesChildren {
-				p[ss.property] = marshalSubSchema(ss)
-			}
-			m["properties"] = p
+	if s.subSchema != nil {
+		m[KEY_SCHEMA] = s.subSchema.String()
+	}
+	if s.id != nil {
+		m[KEY_ID] = *s.id
+	}
+	if s.title != nil {
+		m[KEY_TITLE] = *s.title
+	}
+	if s.description != nil {
+		m[KEY_DESCRIPTION] = *s.description
+	}
+	if s.comment != nil {
+		m[KEY_COMMENT] = *s.comment
+	}
+	if s.defaultValue != nil {
+		var value interface{}
+		if err := json.Unmarshal([]byte(*s.defaultValue), &value); err == nil {
+			m[KEY_DEFAULT] = value
 		}
+	}
+
+	if len(s.types.types) == 1 {
+		m[KEY_TYPE] = s.types.types[0]
+	} else if len(s.types.types) > 1 {
+		m[KEY_TYPE] = s.types.types
+	}
 
-		if s.minProperties != nil {
-			m["minProperties"] = s.minProperties
+	// object
+
+	if len(s.propertiesChildren) != 0 {
+		p := make(map[string]interface{})
+		for _, ss := range s.propertiesChildren {
+			p[ss.property] = marshalSubSchema(ss)
 		}
+		m[KEY_PROPERTIES] = p
+	}
 
-		if s.maxProperties != nil {
-			m["maxProperties"] = s.maxProperties
+	if len(s.patternProperties) != 0 {
+		p := make(map[string]interface{})
+		for k, ss := range s.patternProperties {
+			p[k] = marshalSubSchema(ss)
 		}
+		m[KEY_PATTERN_PROPERTIES] = p
+	}
 
-		if s.additionalProperties != nil {
-			if ss, ok := s.additionalProperties.(*subSchema); ok {
-				m["additionalProperties"] = marshalSubSchema(ss)
-			} else {
-				m["additionalProperties"] = s.additionalProperties
-			}
+	if s.minProperties != nil {
+		m[KEY_MIN_PROPERTIES] = *s.minProperties
+	}
+	if s.maxProperties != nil {
+		m[KEY_MAX_PROPERTIES] = *s.maxProperties
+	}
+
+	if s.additionalProperties != nil {
+		if ss, ok := s.additionalProperties.(*subSchema); ok {
+			m[KEY_ADDITIONAL_PROPERTIES] = marshalSubSchema(ss)
+		} else {
+			m[KEY_ADDITIONAL_PROPERTIES] = s.additionalProperties
 		}
+	}
 
-		if s.dependencies != nil {
-			if ss, ok := s.additionalProperties.(*subSchema); ok {
-				m["dependencies"] = marshalSubSchema(ss)
+	if s.dependencies != nil {
+		d := make(map[string]interface{})
+		for k, dependency := range s.dependencies {
+			if ss, ok := dependency.(*subSchema); ok {
+				d[k] = marshalSubSchema(ss)
 			} else {
-				m["dependencies"] = s.dependencies
+				d[k] = dependency
 			}
 		}
+		m[KEY_DEPENDENCIES] = d
+	}
 
-		if len(s.required) != 0 {
-			m["required"] = s.required
+	if s.dependentSchemas != nil {
+		d := make(map[string]interface{})
+		for k, ss := range s.dependentSchemas {
+			d[k] = marshalSubSchema(ss)
 		}
+		m[KEY_DEPENDENT_SCHEMAS] = d
 	}
 
-	if s.types.Contains(TYPE_ARRAY) {
-		if len(s.itemsChildren) != 0 {
-			var items []interface{}
-			for _, si := range s.itemsChildren {
-				items = append(items, marshalSubSchema(si))
-			}
-			m["items"] = items
-		}
+	if s.propertyNames != nil {
+		m[KEY_PROPERTY_NAMES] = marshalSubSchema(s.propertyNames)
+	}
 
-		if s.minItems != nil {
-			m["minItems"] = s.minItems
-		}
-		if s.maxItems != nil {
-			m["maxItems"] = s.maxItems
-		}
+	if len(s.required) != 0 {
+		m[KEY_REQUIRED] = s.required
+	}
 
-		if s.additionalItems != nil {
-			m["additionalItems"] = s.additionalItems
-		}
+	// array
 
-		if s.uniqueItems != nil {
-			m["uniqueItems"] = s.uniqueItems
+	if len(s.itemsChildren) != 0 {
+		if s.itemsChildrenIsSingleSchema {
+			m[KEY_ITEMS] = marshalSubSchema(s.itemsChildren[0])
+		} else {
+			m[KEY_ITEMS] = marshalSubSchemas(s.itemsChildren)
 		}
 	}
+	if s.itemsBoolean != nil {
+		m[KEY_ITEMS] = *s.itemsBoolean
+	}
 
-	if s.types.Contains(TYPE_STRING) {
-		if s.minLength != nil {
-			m["minLength"] = s.minLength
-		}
-		if s.maxLength != nil {
-			m["maxLength"] = s.maxLength
-		}
-		if s.pattern != nil {
-			m["pattern"] = s.pattern.String()
-		}
+	if s.minItems != nil {
+		m[KEY_MIN_ITEMS] = *s.minItems
+	}
+	if s.maxItems != nil {
+		m[KEY_MAX_ITEMS] = *s.maxItems
 	}
 
-	if s.types.Contains(TYPE_INTEGER) || s.types.Contains(TYPE_NUMBER) {
-		if s.multipleOf != nil {
-			m["multipleOf"] = s.multipleOf
+	if s.additionalItems != nil {
+		if ss, ok := s.additionalItems.(*subSchema); ok {
+			m[KEY_ADDITIONAL_ITEMS] = marshalSubSchema(ss)
+		} else {
+			m[KEY_ADDITIONAL_ITEMS] = s.additionalItems
 		}
+	}
 
-		if s.maximum != nil {
-			m["maximum"] = s.maximum
-		}
+	if s.uniqueItems != nil {
+		m[KEY_UNIQUE_ITEMS] = *s.uniqueItems
+	}
+	if s.sorted != nil {
+		m[KEY_X_SORTED] = *s.sorted
+	}
+	if s.propertyOrder != nil {
+		m[KEY_X_PROPERTY_ORDER] = s.propertyOrder
+	}
+	if s.sortedBy != nil {
+		m[KEY_X_SORTED_BY] = *s.sortedBy
+	}
+	if s.errorMessage != nil {
+		m[KEY_X_ERROR_MESSAGE] = *s.errorMessage
+	} else if s.errorMessages != nil {
+		m[KEY_X_ERROR_MESSAGE] = s.errorMessages
+	}
 
-		if s.exclusiveMaximum != nil {
-			m["exclusiveMaximum"] = s.exclusiveMaximum
-		}
+	// string
 
-		if s.minimum != nil {
-			m["minimum"] = s.minimum
-		}
+	if s.minLength != nil {
+		m[KEY_MIN_LENGTH] = *s.minLength
+	}
+	if s.maxLength != nil {
+		m[KEY_MAX_LENGTH] = *s.maxLength
+	}
+	if s.pattern != nil {
+		m[KEY_PATTERN] = s.pattern.String()
+	}
+	if s.format != nil {
+		m[KEY_FORMAT] = *s.format
+	}
+	if s.anyFormat != nil {
+		m[KEY_X_ANY_FORMAT] = s.anyFormat
+	}
+	if s.contentEncoding != nil {
+		m[KEY_CONTENT_ENCODING] = *s.contentEncoding
+	}
+	if s.contentMediaType != nil {
+		m[KEY_CONTENT_MEDIA_TYPE] = *s.contentMediaType
+	}
 
-		if s.exclusiveMinimum != nil {
-			m["exclusiveMinimum"] = s.exclusiveMinimum
+	// number / integer
+
+	if s.multipleOf != nil {
+		m[KEY_MULTIPLE_OF] = *s.multipleOf
+	}
+	if s.maximum != nil {
+		m[KEY_MAXIMUM] = *s.maximum
+	}
+	if s.exclusiveMaximum != nil {
+		m[KEY_EXCLUSIVE_MAXIMUM] = *s.exclusiveMaximum
+	}
+	if s.minimum != nil {
+		m[KEY_MINIMUM] = *s.minimum
+	}
+	if s.exclusiveMinimum != nil {
+		m[KEY_EXCLUSIVE_MINIMUM] = *s.exclusiveMinimum
+	}
+	if s.numberFormat != nil {
+		m[KEY_X_FORMAT] = *s.numberFormat
+	}
+	if s.discriminator != nil {
+		discriminator := map[string]interface{}{KEY_PROPERTY_NAME: s.discriminator.PropertyName}
+		if s.discriminator.Mapping != nil {
+			discriminator[KEY_MAPPING] = s.discriminator.Mapping
 		}
+		m[KEY_X_DISCRIMINATOR] = discriminator
 	}
+	if s.maxDecimals != nil {
+		m[KEY_X_MAX_DECIMALS] = *s.maxDecimals
+	}
+
+	// all
 
 	if s.enum != nil {
-		m["enum"] = s.enum
+		m[KEY_ENUM] = s.enumValues()
+	}
+
+	// subSchema
+
+	if len(s.oneOf) != 0 {
+		m[KEY_ONE_OF] = marshalSubSchemas(s.oneOf)
+	}
+	if len(s.anyOf) != 0 {
+		m[KEY_ANY_OF] = marshalSubSchemas(s.anyOf)
+	}
+	if len(s.allOf) != 0 {
+		m[KEY_ALL_OF] = marshalSubSchemas(s.allOf)
+	}
+	if s.not != nil {
+		m[KEY_NOT] = marshalSubSchema(s.not)
+	}
+
+	// custom keywords
+
+	for keyword, value := range s.keywordValues {
+		m[keyword] = value
 	}
 
 	return m
@@ -268,6 +526,23 @@ func (s *subSchema) AddEnum(i interface{}) error {
 	return nil
 }
 
+// Returns the members of the enum, decoded from the JSON strings they are stored as
+func (s *subSchema) enumValues() []interface{} {
+
+	var enum []interface{}
+	for _, e := range s.enum {
+		var value interface{}
+		if err := json.Unmarshal([]byte(e), &value); err == nil {
+			enum = append(enum, value)
+		}
+	}
+
+	return enum
+}
+
+// Tells whether a value is a member of the enum. Values are compared in their
+// canonical JSON form, so objects match whatever the order of their keys
+// while the order of array items matters.
 func (s *subSchema) ContainsEnum(i interface{}) (bool, error) {
 
 	is, err := marshalToJsonString(i)
@@ -278,6 +553,51 @@ func (s *subSchema) ContainsEnum(i interface{}) (bool, error) {
 	return isStringInSlice(s.enum, *is), nil
 }
 
+// Tells whether a number is at most epsilon away from a number of the enum,
+// see ValidateOptions.NumericEpsilon
+func (s *subSchema) containsEnumNumberWithin(value interface{}, epsilon float64) bool {
+
+	number, ok := value.(float64)
+	if !ok {
+		return false
+	}
+
+	for _, member := range s.enum {
+		var candidate float64
+		if json.Unmarshal([]byte(member), &candidate) == nil && math.Abs(number-candidate) <= epsilon {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Most edits between a string and the member of its enum suggested instead,
+// see ValidateOptions.SuggestEnum
+const ENUM_SUGGESTION_MAX_DISTANCE = 2
+
+// The string of the enum closest to value, the first one in case of a tie,
+// when it is close enough to be a likely fix
+func (s *subSchema) enumSuggestion(value string) (string, bool) {
+
+	suggestion, best := "", ENUM_SUGGESTION_MAX_DISTANCE+1
+	for _, member := range s.enum {
+		var candidate string
+		if json.Unmarshal([]byte(member), &candidate) != nil {
+			continue
+		}
+		if distance := editDistance(value, candidate); distance < best {
+			suggestion, best = candidate, distance
+		}
+	}
+
+	// fixing most of the string is a rewrite rather than a typo
+	if best > ENUM_SUGGESTION_MAX_DISTANCE || 2*best >= len([]rune(value)) {
+		return "", false
+	}
+	return suggestion, true
+}
+
 func (s *subSchema) AddOneOf(subSchema *subSchema) {
 	s.oneOf = append(s.oneOf, subSchema)
 }
@@ -294,6 +614,82 @@ func (s *subSchema) SetNot(subSchema *subSchema) {
 	s.not = subSchema
 }
 
+// validation : number / integer
+// The getters return whether the keyword is set along with its value.
+// The setters change the subSchema in place : they must not be used on a
+// schema that is validating documents, ex through Result.AppliedSchema, but
+// on one being built or on a copy given by Schema.Clone.
+
+func (s *subSchema) SetMultipleOf(value float64) error {
+
+	if value <= 0 {
+		return errors.New(fmt.Sprintf(ERROR_MESSAGE_X_MUST_BE_STRICTLY_GREATER_THAN_0, KEY_MULTIPLE_OF))
+	}
+
+	s.multipleOf = &value
+
+	return nil
+}
+
+func (s *subSchema) MultipleOf() (value float64, set bool) {
+	if s.multipleOf == nil {
+		return 0, false
+	}
+	return *s.multipleOf, true
+}
+
+// Comment returns the text of the $comment keyword
+func (s *subSchema) Comment() (value string, set bool) {
+	if s.comment == nil {
+		return "", false
+	}
+	return *s.comment, true
+}
+
+func (s *subSchema) SetMinimum(value float64) {
+	s.minimum = &value
+}
+
+func (s *subSchema) Minimum() (value float64, set bool) {
+	if s.minimum == nil {
+		return 0, false
+	}
+	return *s.minimum, true
+}
+
+func (s *subSchema) SetExclusiveMinimum(value bool) {
+	s.exclusiveMinimum = &value
+}
+
+func (s *subSchema) ExclusiveMinimum() (value bool, set bool) {
+	if s.exclusiveMinimum == nil {
+		return false, false
+	}
+	return *s.exclusiveMinimum, true
+}
+
+func (s *subSchema) SetMaximum(value float64) {
+	s.maximum = &value
+}
+
+func (s *subSchema) Maximum() (value float64, set bool) {
+	if s.maximum == nil {
+		return 0, false
+	}
+	return *s.maximum, true
+}
+
+func (s *subSchema) SetExclusiveMaximum(value bool) {
+	s.exclusiveMaximum = &value
+}
+
+func (s *subSchema) ExclusiveMaximum() (value bool, set bool) {
+	if s.exclusiveMaximum == nil {
+		return false, false
+	}
+	return *s.exclusiveMaximum, true
+}
+
 func (s *subSchema) AddRequired(value string) error {
 
 	if isStringInSlice(s.required, value) {
@@ -317,6 +713,221 @@ func (s *subSchema) AddPropertiesChild(child *subSchema) {
 	s.propertiesChildren = append(s.propertiesChildren, child)
 }
 
+// Tells whether the subSchema explicitly allows null, with its type or its enum.
+// A subSchema with anyOf, oneOf or allOf leaves the decision to these subSchemas.
+func (s *subSchema) allowsNull() bool {
+
+	if s.types.Contains(TYPE_NULL) || len(s.anyOf) > 0 || len(s.oneOf) > 0 || len(s.allOf) > 0 {
+		return true
+	}
+
+	allowed, _ := s.ContainsEnum(nil)
+	return allowed
+}
+
+// Returns the message x-errorMessage gives to the errors of the keyword
+func (s *subSchema) errorMessageFor(keyword string) (string, bool) {
+	if s.errorMessage != nil {
+		return *s.errorMessage, true
+	}
+	message, ok := s.errorMessages[keyword]
+	return message, ok
+}
+
+// Returns the title of the subSchema, or of the subSchema it references,
+// nil when the $ref form a cycle without title
+func (s *subSchema) resolvedTitle() *string {
+	visited := make(map[*subSchema]bool)
+	for s.title == nil && s.refSchema != nil {
+		if visited[s] {
+			return nil
+		}
+		visited[s] = true
+		s = s.refSchema
+	}
+	return s.title
+}
+
+// Returns the subSchema the chain of $ref starting at the subSchema ends
+// with, the subSchema itself when it is not a $ref, nil when the chain is a
+// cycle, as {"$ref": "#"}
+func (s *subSchema) resolvedRef() *subSchema {
+	visited := make(map[*subSchema]bool)
+	for s.refSchema != nil {
+		if visited[s] {
+			return nil
+		}
+		visited[s] = true
+		s = s.refSchema
+	}
+	return s
+}
+
+// Tells whether the subSchema is typed as a number or an integer but not as a
+// string, see ValidateOptions.NumericStrings
+func (s *subSchema) expectsNumber() bool {
+	return (s.types.Contains(TYPE_NUMBER) || s.types.Contains(TYPE_INTEGER)) && !s.types.Contains(TYPE_STRING)
+}
+
+// Returns the title of a property declared in "properties", nil if it has none
+func (s *subSchema) propertyTitle(name string) *string {
+	if child := s.propertyChild(name); child != nil {
+		return child.resolvedTitle()
+	}
+	return nil
+}
+
+// Returns the subSchemas held by the keywords of the subSchema, the referenced
+// one included. The same subSchema may be listed several times.
+func (s *subSchema) children() []*subSchema {
+
+	var children []*subSchema
+
+	if s.refSchema != nil {
+		children = append(children, s.refSchema)
+	}
+	for _, k := range sortedKeys(s.definitions) {
+		children = append(children, s.definitions[k])
+	}
+	children = append(children, s.definitionsChildren...)
+	children = append(children, s.itemsChildren...)
+	children = append(children, s.propertiesChildren...)
+	for _, k := range sortedKeys(s.dependencies) {
+		if dependency, ok := s.dependencies[k].(*subSchema); ok {
+			children = append(children, dependency)
+		}
+	}
+	for _, k := range sortedKeys(s.dependentSchemas) {
+		children = append(children, s.dependentSchemas[k])
+	}
+	if s.propertyNames != nil {
+		children = append(children, s.propertyNames)
+	}
+	if additionalProperties, ok := s.additionalProperties.(*subSchema); ok {
+		children = append(children, additionalProperties)
+	}
+	for _, k := range sortedKeys(s.patternProperties) {
+		children = append(children, s.patternProperties[k])
+	}
+	if additionalItems, ok := s.additionalItems.(*subSchema); ok {
+		children = append(children, additionalItems)
+	}
+	children = append(children, s.oneOf...)
+	children = append(children, s.anyOf...)
+	children = append(children, s.allOf...)
+	if s.not != nil {
+		children = append(children, s.not)
+	}
+
+	return children
+}
+
+// Returns the location of a child of the subSchema,
+// tokens being the path from the subSchema to this child
+func (s *subSchema) childLocation(tokens ...string) string {
+
+	location := s.location
+	for _, token := range tokens {
+		location += "/" + escapeJsonPointerToken(token)
+	}
+
+	return location
+}
+
+func (s *subSchema) hasPropertyChild(name string) bool {
+	return s.propertyChild(name) != nil
+}
+
+// Returns the subSchema of the property declared in "properties" that a key
+// matches regardless of case, see ValidateOptions.CaseInsensitiveProperties.
+// The property spelled as the key is preferred, then the first one in byte
+// order : the properties are not kept in the order they are declared.
+func (s *subSchema) propertyChildFold(key string) *subSchema {
+
+	if child := s.propertyChild(key); child != nil {
+		return child
+	}
+	var match *subSchema
+	for _, child := range s.propertiesChildren {
+		if strings.EqualFold(child.property, key) && (match == nil || child.property < match.property) {
+			match = child
+		}
+	}
+
+	return match
+}
+
+// Returns the subSchema of a property declared in "properties", nil if not declared
+func (s *subSchema) propertyChild(name string) *subSchema {
+
+	for _, child := range s.propertiesChildren {
+		if child.property == name {
+			return child
+		}
+	}
+
+	return nil
+}
+
+// Returns the sorted names of "properties" and keys of "patternProperties",
+// the properties allowed when additionalProperties is false
+func (s *subSchema) allowedProperties() map[string][]string {
+
+	properties := []string{}
+	for _, child := range s.propertiesChildren {
+		properties = append(properties, child.property)
+	}
+	sort.Strings(properties)
+
+	patterns := sortedKeys(s.patternProperties)
+	if patterns == nil {
+		patterns = []string{}
+	}
+
+	return map[string][]string{KEY_PROPERTIES: properties, KEY_PATTERN_PROPERTIES: patterns}
+}
+
+// Returns the requirement of the errors of additionalProperties false, the
+// properties allowed as much as detail tells
+func (s *subSchema) allowedPropertiesRequirement(detail AdditionalPropertyErrorDetail) interface{} {
+	switch detail {
+	case ALLOWED_PROPERTIES_NONE:
+		return nil
+	case ALLOWED_PROPERTIES_FULL:
+		return s.allowedProperties()
+	}
+	return map[string]int{KEY_PROPERTIES: len(s.propertiesChildren), KEY_PATTERN_PROPERTIES: len(s.patternProperties)}
+}
+
+// Location returns the URI of the subSchema : the one of the document that
+// defines it followed by its JSON pointer, ex "#/definitions/address".
+func (s *subSchema) Location() string {
+	return s.location
+}
+
+// Tells whether a property is defined by the subSchema, either in "properties"
+// or by a key of "patternProperties" matching its name.
+// A subSchema that is a $ref is checked through the referenced subSchema,
+// false when the $ref form a cycle.
+func (s *subSchema) HasProperty(name string) bool {
+
+	if s = s.resolvedRef(); s == nil {
+		return false
+	}
+
+	if s.hasPropertyChild(name) {
+		return true
+	}
+
+	for pk := range s.patternProperties {
+		if matches, _ := regexp.MatchString(pk, name); matches {
+			return true
+		}
+	}
+
+	return false
+}
+
 func (s *subSchema) PatternPropertiesString() string {
 
 	if s.patternProperties == nil || len(s.patternProperties) == 0 {
@@ -0,0 +1,282 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for subSchema helpers.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalSubSchemaRoundTrip(t *testing.T) {
+
+	source := `{
+		"$schema": "http://json-schema.org/draft-04/schema",
+		"$id": "http://example.com/order.json",
+		"title": "order",
+		"description": "an order",
+		"type": ["object", "null"],
+		"properties": {
+			"id": {"type": "integer", "minimum": 1, "exclusiveMinimum": true, "maximum": 100, "multipleOf": 1},
+			"code": {"type": "string", "minLength": 2, "maxLength": 5, "pattern": "^[A-Z]+$"},
+			"tags": {"type": "array", "items": {"type": "string"}, "minItems": 1, "maxItems": 3, "uniqueItems": true},
+			"pair": {"items": [{"type": "string"}, {"type": "number"}], "additionalItems": {"type": "boolean"}},
+			"status": {"enum": ["open", 2, null, {"a": [1]}]}
+		},
+		"patternProperties": {"^x-": {"not": {"type": "null"}}},
+		"additionalProperties": {"type": "string"},
+		"dependencies": {"card": ["billing"], "coupon": {"required": ["total"]}},
+		"required": ["id"],
+		"minProperties": 1,
+		"maxProperties": 10,
+		"oneOf": [{"required": ["code"]}, {"required": ["tags"]}],
+		"anyOf": [{"minProperties": 1}],
+		"allOf": [{"maxProperties": 10}]
+	}`
+
+	schema, err := NewSchema(NewStringLoader(source))
+	assert.Nil(t, err)
+
+	marshaled, err := json.Marshal(marshalSubSchema(schema.rootSchema))
+	assert.Nil(t, err)
+
+	var expected, given interface{}
+	assert.Nil(t, json.Unmarshal([]byte(source), &expected))
+	assert.Nil(t, json.Unmarshal(marshaled, &given))
+
+	assert.Equal(t, expected, given)
+}
+
+func TestHasProperty(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {"id": {}, "address": {"$ref": "#/definitions/address"}},
+		"patternProperties": {"^x-": {}},
+		"definitions": {"address": {"properties": {"street": {}}}}
+	}`))
+	assert.Nil(t, err)
+
+	assert.True(t, schema.HasProperty("id"))
+	assert.True(t, schema.HasProperty("x-trace"))
+	assert.False(t, schema.HasProperty("name"))
+	assert.False(t, schema.HasProperty("street"))
+
+	address := schema.rootSchema.propertyChild("address")
+	assert.True(t, address.HasProperty("street"))
+	assert.False(t, address.HasProperty("id"))
+
+	// the $ref forming a cycle lead to no property, nor title
+	for _, document := range []string{
+		`{"$ref": "#"}`,
+		`{"definitions": {"a": {"$ref": "#/definitions/b"}, "b": {"$ref": "#/definitions/a"}}, "properties": {"p": {"$ref": "#/definitions/a"}}}`,
+	} {
+		schema, err := NewSchema(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.False(t, schema.HasProperty("zz"), document)
+		assert.Nil(t, schema.rootSchema.resolvedTitle(), document)
+		assert.Nil(t, schema.rootSchema.propertyTitle("p"), document)
+		_, ok := schema.EnumAt("/properties/p")
+		assert.False(t, ok, document)
+	}
+}
+
+func TestNumericAccessors(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"minimum": 1, "exclusiveMinimum": true}`))
+	assert.Nil(t, err)
+	s := schema.rootSchema
+
+	minimum, set := s.Minimum()
+	assert.True(t, set)
+	assert.Equal(t, float64(1), minimum)
+	exclusive, set := s.ExclusiveMinimum()
+	assert.True(t, set)
+	assert.True(t, exclusive)
+	_, set = s.Maximum()
+	assert.False(t, set)
+	_, set = s.ExclusiveMaximum()
+	assert.False(t, set)
+	_, set = s.MultipleOf()
+	assert.False(t, set)
+
+	// the setters apply to a copy, the schema may be in use
+	clone := schema.Clone()
+	c := clone.rootSchema
+	c.SetMaximum(10)
+	c.SetExclusiveMaximum(false)
+	c.SetExclusiveMinimum(false)
+	assert.Nil(t, c.SetMultipleOf(0.5))
+	assert.NotNil(t, c.SetMultipleOf(0))
+
+	multipleOf, set := c.MultipleOf()
+	assert.True(t, set)
+	assert.Equal(t, 0.5, multipleOf)
+	_, set = s.MultipleOf()
+	assert.False(t, set)
+
+	for document, valid := range map[string]bool{`1`: true, `10`: true, `10.5`: false, `1.25`: false} {
+		result, err := clone.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	result, err := schema.Validate(NewStringLoader(`1`))
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+}
+
+func TestEnumOfObjectsAndArrays(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"enum": [{"a": 1, "b": {"c": [1, 2], "d": null}}, [1, {"x": 1, "y": 2}]]}`))
+	assert.Nil(t, err)
+
+	for document, valid := range map[string]bool{
+		`{"b": {"d": null, "c": [1, 2]}, "a": 1}`:   true,
+		`{"b": {"d": null, "c": [1, 2]}, "a": 1.0}`: true,
+		`{"b": {"d": null, "c": [2, 1]}, "a": 1}`:   false,
+		`{"a": 1}`:              false,
+		`[1, {"y": 2, "x": 1}]`: true,
+		`[{"y": 2, "x": 1}, 1]`: false,
+	} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	type point struct {
+		Y int `json:"y"`
+		X int `json:"x"`
+	}
+	contains, err := schema.rootSchema.ContainsEnum([]interface{}{1, point{X: 1, Y: 2}})
+	assert.Nil(t, err)
+	assert.True(t, contains)
+}
+
+func TestComment(t *testing.T) {
+
+	schema, err := NewSchemaWithOptions(NewStringLoader(`{
+		"$comment": "ids are issued by the billing service",
+		"properties": {"id": {"type": "string", "$comment": "uuid"}}
+	}`), SchemaLoaderOptions{StrictSchema: true})
+	assert.Nil(t, err)
+	assert.Empty(t, schema.Warnings())
+
+	comment, set := schema.rootSchema.Comment()
+	assert.True(t, set)
+	assert.Equal(t, "ids are issued by the billing service", comment)
+	comment, set = schema.rootSchema.propertyChild("id").Comment()
+	assert.True(t, set)
+	assert.Equal(t, "uuid", comment)
+
+	// no effect on validation
+	result, err := schema.Validate(NewStringLoader(`{"id": "uuid"}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	_, err = NewSchema(NewStringLoader(`{"$comment": 1}`))
+	assert.EqualError(t, err, "$comment must be of type string")
+}
+
+func TestMetaSchemaAndID(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"$schema": "http://json-schema.org/draft-04/schema#", "$id": "http://example.com/a.json", "type": "string"}`))
+	assert.Nil(t, err)
+	// as a reference, without its empty fragment
+	assert.Equal(t, "http://json-schema.org/draft-04/schema", schema.MetaSchema())
+	assert.Equal(t, "http://example.com/a.json", schema.ID())
+
+	schema, err = NewSchema(NewStringLoader(`{"type": "string"}`))
+	assert.Nil(t, err)
+	assert.Equal(t, "", schema.MetaSchema())
+	assert.Equal(t, "", schema.ID())
+
+	_, err = NewSchema(NewStringLoader(`{"$schema": 4}`))
+	assert.EqualError(t, err, `$schema must be of type string`)
+}
+
+func TestEnumAt(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"status": {"enum": ["active", "inactive"]},
+			"size": {"$ref": "#/definitions/size"},
+			"tags": {"items": {"enum": [1, "two", null]}},
+			"name": {"type": "string"}
+		},
+		"definitions": {"size": {"enum": ["S", "M", "L"]}}
+	}`))
+	assert.Nil(t, err)
+
+	enum, ok := schema.EnumAt("/properties/status")
+	assert.True(t, ok)
+	assert.Equal(t, []interface{}{"active", "inactive"}, enum)
+
+	enum, ok = schema.EnumAt("#/properties/size")
+	assert.True(t, ok)
+	assert.Equal(t, []interface{}{"S", "M", "L"}, enum)
+
+	enum, ok = schema.EnumAt("/properties/tags/items")
+	assert.True(t, ok)
+	assert.Equal(t, []interface{}{float64(1), "two", nil}, enum)
+
+	for _, pointer := range []string{"/properties/name", "/properties/unknown", "", "#"} {
+		_, ok = schema.EnumAt(pointer)
+		assert.False(t, ok, pointer)
+	}
+}
+
+func TestDefs(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"$schema": "https://json-schema.org/draft/2019-09/schema",
+		"$vocabulary": {"https://json-schema.org/draft/2019-09/vocab/core": true},
+		"$defs": {
+			"size": {"enum": ["s", "m", "l"]},
+			"item": {"properties": {"size": {"$ref": "#/$defs/size"}}, "required": ["size"]}
+		},
+		"items": {"$ref": "#/$defs/item"}
+	}`))
+	assert.Nil(t, err)
+
+	result, err := schema.Validate(NewStringLoader(`[{"size": "m"}]`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	result, err = schema.Validate(NewStringLoader(`[{"size": "xl"}, {}]`))
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 2)
+
+	enum, ok := schema.EnumAt("#/$defs/size")
+	assert.True(t, ok)
+	assert.Equal(t, []interface{}{"s", "m", "l"}, enum)
+
+	// as definitions
+	_, err = NewSchema(NewStringLoader(`{"$defs": {"a": 1}}`))
+	assert.EqualError(t, err, `$defs must be of type array of schemas`)
+	_, err = NewSchema(NewStringLoader(`{"$vocabulary": true}`))
+	assert.EqualError(t, err, `$vocabulary must be of type object`)
+}
@@ -26,10 +26,18 @@
 package gojsonschema
 
 import (
+	"encoding"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
+	"math/big"
 	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/xeipuuv/gojsonreference"
 )
 
 func isKind(what interface{}, kind reflect.Kind) bool {
@@ -42,14 +50,56 @@ func existsMapKey(m map[string]interface{}, k string) bool {
 }
 
 func isStringInSlice(s []string, what string) bool {
+	return indexOfString(s, what) >= 0
+}
+
+// Returns the index of a string in a slice, -1 if missing
+func indexOfString(s []string, what string) int {
 	for i := range s {
 		if s[i] == what {
-			return true
+			return i
 		}
 	}
-	return false
+	return -1
+}
+
+// Returns the keys of a map keyed by strings, sorted
+func sortedKeys(m interface{}) []string {
+	var keys []string
+	for _, k := range reflect.ValueOf(m).MapKeys() {
+		keys = append(keys, k.String())
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Escapes a JSON pointer reference token, as defined by RFC 6901
+func escapeJsonPointerToken(token string) string {
+	return strings.Replace(strings.Replace(token, "~", "~0", -1), "/", "~1", -1)
 }
 
+// Returns the location of the subSchema a reference points to, the reference
+// itself with an empty fragment when it points to the root of its document
+func referenceLocation(reference gojsonreference.JsonReference) string {
+	location := reference.String()
+	if !strings.Contains(location, "#") {
+		location += "#"
+	}
+	return location
+}
+
+// Returns the location of the root of a document, its reference without fragment
+func documentLocation(reference gojsonreference.JsonReference) string {
+	location := reference.String()
+	if i := strings.Index(location, "#"); i >= 0 {
+		location = location[:i]
+	}
+	return location + "#"
+}
+
+// Marshals a value to a canonical JSON string : whatever the Go types of the value
+// (decoded JSON, structs, typed maps or slices, numbers of any kind), equal JSON
+// values give the same string, with sorted keys and numbers written alike.
 func marshalToJsonString(value interface{}) (*string, error) {
 
 	mBytes, err := json.Marshal(value)
@@ -57,16 +107,130 @@ func marshalToJsonString(value interface{}) (*string, error) {
 		return nil, err
 	}
 
+	document, err := decodeJSONUseNumber(mBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	mBytes, err = json.Marshal(canonicalizeJsonNumbers(document))
+	if err != nil {
+		return nil, err
+	}
+
 	sBytes := string(mBytes)
 	return &sBytes, nil
 }
 
+// Copies a decoded JSON value, with its objects and arrays
+func copyJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		copied := make(map[string]interface{}, len(v))
+		for key, child := range v {
+			copied[key] = copyJSON(child)
+		}
+		return copied
+	case []interface{}:
+		copied := make([]interface{}, len(v))
+		for i, child := range v {
+			copied[i] = copyJSON(child)
+		}
+		return copied
+	}
+	return value
+}
+
+// Tells whether two values are the same JSON value, the rule of enum and
+// uniqueItems : the numbers are equal by value however they are written or
+// typed, as 1, 1.0, 1e0, json.Number("1") and int64(1), and the objects
+// whatever the order of their keys. A value that cannot be encoded as JSON
+// equals nothing. Both keywords compare the canonical strings of the values
+// rather than calling it, to check a value against many at once.
+func jsonDeepEqual(a interface{}, b interface{}) bool {
+
+	as, err := marshalToJsonString(a)
+	if err != nil {
+		return false
+	}
+	bs, err := marshalToJsonString(b)
+	if err != nil {
+		return false
+	}
+
+	return *as == *bs
+}
+
+// Rewrites the numbers of a document decoded with json.Number in a canonical form :
+// integers with their digits only (1.0 and 1e2 become 1 and 100),
+// other numbers as encoding/json writes a float64.
+func canonicalizeJsonNumbers(document interface{}) interface{} {
+
+	switch d := document.(type) {
+
+	case map[string]interface{}:
+		for k, v := range d {
+			d[k] = canonicalizeJsonNumbers(v)
+		}
+
+	case []interface{}:
+		for i, v := range d {
+			d[i] = canonicalizeJsonNumbers(v)
+		}
+
+	case json.Number:
+		r, ok := new(big.Rat).SetString(d.String())
+		if !ok {
+			return d
+		}
+		if r.IsInt() {
+			return json.Number(r.Num().String())
+		}
+		// out of the range of a float64, written as is
+		if _, err := d.Float64(); err != nil {
+			return d
+		}
+		f, _ := r.Float64()
+		return f
+	}
+
+	return document
+}
+
 // same as ECMA Number.MAX_SAFE_INTEGER and Number.MIN_SAFE_INTEGER
 const (
 	max_json_float = float64(1<<53 - 1)  // 9007199254740991.0 	 2^53 - 1
 	min_json_float = -float64(1<<53 - 1) //-9007199254740991.0	-2^53 - 1
 )
 
+// Parses a string holding a JSON number, as in "9007199254740993", telling
+// whether it is an integer from its digits, whatever the float64 rounding.
+// The other numeric notations of Go, like "0x10", " 1" or "Inf", are rejected.
+func parseJSONNumber(s string) (f float64, isInteger bool, ok bool) {
+
+	if !json.Valid([]byte(s)) || s == "" || (s[0] != '-' && (s[0] < '0' || s[0] > '9')) {
+		return 0, false, false
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, false, false
+	}
+	r, _ := new(big.Rat).SetString(s)
+
+	return f, r.IsInt(), true
+}
+
+// Returns the value of the first key of an object, in sorted order, equal to
+// name regardless of case
+func valueOfKeyFold(object map[string]interface{}, name string) (interface{}, bool) {
+	for _, key := range sortedKeys(object) {
+		if strings.EqualFold(key, name) {
+			return object[key], true
+		}
+	}
+	return nil, false
+}
+
 // allow for integers [-2^53, 2^53-1] inclusive
 func isFloat64AnInteger(f float64) bool {
 
@@ -77,6 +241,104 @@ func isFloat64AnInteger(f float64) bool {
 	return f == float64(int64(f)) || f == float64(uint64(f))
 }
 
+// The number of digits after the decimal point of a number, as written in the
+// shortest decimal that parses back to it. For the numbers of a JSON document
+// of up to 15 significant digits, this is the number as written in the
+// document : 19.99 has 2 decimals, where it is not a multiple of 0.01 as a float.
+func decimalPlaces(f float64) int {
+	s := strconv.FormatFloat(f, 'f', -1, 64)
+	if dot := strings.IndexByte(s, '.'); dot >= 0 {
+		return len(s) - dot - 1
+	}
+	return 0
+}
+
+// The number of digits after the decimal point of a JSON number as written,
+// trailing zeros aside, without the rounding of a float : 19.990 and 1999e-2
+// have 2 decimals, 0.1000000000000000001 has 19.
+func decimalPlacesOfText(number string) int {
+
+	mantissa, exponent := number, 0
+	if e := strings.IndexAny(number, "eE"); e >= 0 {
+		mantissa = number[:e]
+		exponent, _ = strconv.Atoi(number[e+1:])
+	}
+
+	digits, scale := mantissa, 0
+	if dot := strings.IndexByte(mantissa, '.'); dot >= 0 {
+		digits, scale = mantissa[:dot]+mantissa[dot+1:], len(mantissa)-dot-1
+	}
+	scale -= exponent
+
+	for scale > 0 && strings.HasSuffix(digits, "0") {
+		digits = digits[:len(digits)-1]
+		scale--
+	}
+	if scale < 0 {
+		return 0
+	}
+	return scale
+}
+
+// The Levenshtein distance between two strings : the least number of runes
+// to insert, delete or substitute to turn one into the other
+func editDistance(a string, b string) int {
+
+	ra, rb := []rune(a), []rune(b)
+
+	// distances from the prefixes of a to the previous and current prefixes of b
+	previous := make([]int, len(ra)+1)
+	current := make([]int, len(ra)+1)
+	for i := range previous {
+		previous[i] = i
+	}
+
+	for j := 1; j <= len(rb); j++ {
+		current[0] = j
+		for i := 1; i <= len(ra); i++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[i] = min3(previous[i]+1, current[i-1]+1, previous[i-1]+cost)
+		}
+		previous, current = current, previous
+	}
+
+	return previous[len(ra)]
+}
+
+func min3(a int, b int, c int) int {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
+
+// The JSON type of a document node, integer for the numbers without decimals
+func jsonTypeOf(what interface{}) string {
+	if what == nil {
+		return TYPE_NULL
+	}
+	switch reflect.ValueOf(what).Kind() {
+	case reflect.Slice:
+		return TYPE_ARRAY
+	case reflect.Map:
+		return TYPE_OBJECT
+	case reflect.Bool:
+		return TYPE_BOOLEAN
+	case reflect.String:
+		return TYPE_STRING
+	}
+	if f, ok := what.(float64); ok && isFloat64AnInteger(f) {
+		return TYPE_INTEGER
+	}
+	return TYPE_NUMBER
+}
+
 func mustBeInteger(what interface{}) *int {
 
 	var number int
@@ -122,6 +384,47 @@ func mustBeNumber(what interface{}) *float64 {
 
 }
 
+// Compares two numbers or two strings, returning -1, 0 or +1.
+// ok is false when the values are not of the same of these types.
+func compareSortKeys(a interface{}, b interface{}) (order int, ok bool) {
+
+	if na, nb := mustBeNumber(a), mustBeNumber(b); na != nil && nb != nil {
+		switch {
+		case *na < *nb:
+			return -1, true
+		case *na > *nb:
+			return 1, true
+		}
+		return 0, true
+	}
+
+	if sa, isString := a.(string); isString {
+		if sb, isString := b.(string); isString {
+			return strings.Compare(sa, sb), true
+		}
+	}
+
+	return 0, false
+}
+
+// Tells whether value is a multiple of divisor. When both are integers the
+// remainder is computed exactly, the quotient of large integers being
+// beyond the precision of a float64.
+func isMultipleOf(value float64, divisor float64) bool {
+
+	if value != math.Trunc(value) || divisor != math.Trunc(divisor) {
+		return isFloat64AnInteger(value / divisor)
+	}
+
+	if math.Abs(value) < 1<<63 && math.Abs(divisor) < 1<<63 {
+		return int64(value)%int64(divisor) == 0
+	}
+
+	v, _ := big.NewFloat(value).Int(nil)
+	d, _ := big.NewFloat(divisor).Int(nil)
+	return new(big.Int).Rem(v, d).Sign() == 0
+}
+
 // formats a number so that it is displayed as the smallest string possible
 func resultErrorFormatNumber(n float64) string {
 
@@ -132,30 +435,71 @@ func resultErrorFormatNumber(n float64) string {
 	return fmt.Sprintf("%g", n)
 }
 
-func convertDocumentNode(val interface{}) interface{} {
+// Converts the Go maps of a document, whatever their keys, to the
+// map[string]interface{} of the JSON objects, their keys being converted as
+// encoding/json does
+func convertDocumentNode(val interface{}) (interface{}, error) {
 
 	if lval, ok := val.([]interface{}); ok {
 
 		res := []interface{}{}
 		for _, v := range lval {
-			res = append(res, convertDocumentNode(v))
+			converted, err := convertDocumentNode(v)
+			if err != nil {
+				return nil, err
+			}
+			res = append(res, converted)
 		}
 
-		return res
+		return res, nil
 
 	}
 
-	if mval, ok := val.(map[interface{}]interface{}); ok {
+	if rval := reflect.ValueOf(val); rval.Kind() == reflect.Map {
 
 		res := map[string]interface{}{}
 
-		for k, v := range mval {
-			res[k.(string)] = convertDocumentNode(v)
+		iter := rval.MapRange()
+		for iter.Next() {
+			k, err := mapKeyString(iter.Key())
+			if err != nil {
+				return nil, err
+			}
+			converted, err := convertDocumentNode(iter.Value().Interface())
+			if err != nil {
+				return nil, err
+			}
+			res[k] = converted
 		}
 
-		return res
+		return res, nil
+
+	}
+
+	return val, nil
+}
+
+// The property name a key of a Go map stands for : strings, integers and
+// encoding.TextMarshaler are accepted, as by encoding/json
+func mapKeyString(key reflect.Value) (string, error) {
+
+	if key.Kind() == reflect.Interface && !key.IsNil() {
+		key = key.Elem()
+	}
+
+	switch key.Kind() {
+	case reflect.String:
+		return key.String(), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(key.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return strconv.FormatUint(key.Uint(), 10), nil
+	}
 
+	if marshaler, ok := key.Interface().(encoding.TextMarshaler); ok {
+		text, err := marshaler.MarshalText()
+		return string(text), err
 	}
 
-	return val
+	return "", errors.New(fmt.Sprintf(ERROR_MESSAGE_INVALID_MAP_KEY_X_OF_TYPE_Y, key.Interface(), key.Type()))
 }
@@ -26,6 +26,8 @@
 package gojsonschema
 
 import (
+	"encoding/json"
+	"fmt"
 	"github.com/stretchr/testify/assert"
 	"math"
 	"testing"
@@ -155,3 +157,210 @@ func TestResultErrorFormatNumber(t *testing.T) {
 	assert.Equal(t, "-4.6116860184273876e+07", resultErrorFormatNumber(-4.611686018427387904e7))
 
 }
+
+func TestMarshalToJsonStringCanonical(t *testing.T) {
+
+	var decoded interface{}
+	err := json.Unmarshal([]byte(`{"b": [1, 2.5, {"y": true, "x": null}], "a": "s"}`), &decoded)
+	assert.Nil(t, err)
+
+	type item struct {
+		Y bool        `json:"y"`
+		X interface{} `json:"x"`
+	}
+
+	values := []interface{}{
+		decoded,
+		map[string]interface{}{
+			"a": "s",
+			"b": []interface{}{int64(1), float32(2.5), map[string]interface{}{"x": nil, "y": true}},
+		},
+		map[string]interface{}{
+			"b": []interface{}{json.Number("1.0"), json.Number("25e-1"), item{Y: true}},
+			"a": "s",
+		},
+		struct {
+			B []interface{} `json:"b"`
+			A string        `json:"a"`
+		}{[]interface{}{uint8(1), 2.5, map[string]bool{"y": true, "x": false}}, "s"},
+	}
+
+	expected := `{"a":"s","b":[1,2.5,{"x":null,"y":true}]}`
+	for i, value := range values[:3] {
+		s, err := marshalToJsonString(value)
+		assert.Nil(t, err)
+		assert.Equal(t, expected, *s, "value %d", i)
+	}
+
+	s, err := marshalToJsonString(values[3])
+	assert.Nil(t, err)
+	assert.Equal(t, `{"a":"s","b":[1,2.5,{"x":false,"y":true}]}`, *s)
+
+	s, err = marshalToJsonString([]interface{}{json.Number("1e2"), json.Number("-0.0"), json.Number("0.10"), uint64(12345678901234567890)})
+	assert.Nil(t, err)
+	assert.Equal(t, `[100,0,0.1,12345678901234567890]`, *s)
+}
+
+func TestJsonDeepEqual(t *testing.T) {
+
+	cases := []struct {
+		a, b  interface{}
+		equal bool
+	}{
+		// numbers, however written or typed
+		{json.Number("1"), 1.0, true},
+		{json.Number("1"), json.Number("1.0"), true},
+		{json.Number("1e0"), json.Number("1"), true},
+		{json.Number("100"), json.Number("1E2"), true},
+		{json.Number("-0"), 0, true},
+		{json.Number("0.10"), 0.1, true},
+		{json.Number("15e-1"), 1.5, true},
+		{int64(1), uint8(1), true},
+		{float32(0.5), 0.5, true},
+		{json.Number("9007199254740993"), json.Number("9007199254740992"), false},
+		{json.Number("9007199254740993"), json.Number("9007199254740993.0"), true},
+		{json.Number("1e400"), json.Number("10e399"), true},
+		{1, 1.5, false},
+		{1, "1", false},
+		// null and booleans
+		{nil, nil, true},
+		{nil, false, false},
+		{true, true, true},
+		{true, false, false},
+		{false, 0, false},
+		// strings
+		{"a", "a", true},
+		{"a", "A", false},
+		{"", nil, false},
+		// arrays, in order
+		{[]interface{}{1, "a"}, []interface{}{json.Number("1.0"), "a"}, true},
+		{[]interface{}{1, "a"}, []interface{}{"a", 1}, false},
+		{[]interface{}{}, []interface{}{nil}, false},
+		{[]int{1, 2}, []interface{}{1.0, 2.0}, true},
+		// objects, whatever the order of their keys
+		{map[string]interface{}{"a": 1, "b": []interface{}{true}}, map[string]interface{}{"b": []interface{}{true}, "a": 1.0}, true},
+		{map[string]interface{}{"a": map[string]interface{}{"x": json.Number("2e0")}}, map[string]interface{}{"a": map[string]interface{}{"x": 2}}, true},
+		{map[string]interface{}{"a": nil}, map[string]interface{}{}, false},
+		{map[string]interface{}{"a": 1}, map[string]interface{}{"a": 1, "b": 2}, false},
+		{map[string]interface{}{}, []interface{}{}, false},
+		// not JSON values
+		{func() {}, func() {}, false},
+	}
+
+	for _, c := range cases {
+		assert.Equal(t, c.equal, jsonDeepEqual(c.a, c.b), "%v and %v", c.a, c.b)
+		assert.Equal(t, c.equal, jsonDeepEqual(c.b, c.a), "%v and %v", c.b, c.a)
+		// as enum tells its members
+		enum := &subSchema{}
+		if enum.AddEnum(c.a) == nil {
+			contains, _ := enum.ContainsEnum(c.b)
+			assert.Equal(t, c.equal, contains, "%v in enum %v", c.b, c.a)
+		}
+	}
+
+	// enum and uniqueItems follow the same rules
+	schema, err := NewSchema(NewStringLoader(`{"enum": [1, {"a": [1.5]}]}`))
+	assert.Nil(t, err)
+	for _, document := range []string{`1.0`, `1e0`, `{"a": [15e-1]}`} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.True(t, result.Valid(), document)
+	}
+	schema, err = NewSchema(NewStringLoader(`{"uniqueItems": true}`))
+	assert.Nil(t, err)
+	result, err := schema.Validate(NewStringLoader(`[{"a": 1, "b": null}, {"b": null, "a": 1e0}]`))
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+}
+
+func TestIsMultipleOf(t *testing.T) {
+
+	assert.True(t, isMultipleOf(10, 5))
+	assert.False(t, isMultipleOf(10, 3))
+	assert.True(t, isMultipleOf(-10, 5))
+	assert.True(t, isMultipleOf(0, 7))
+
+	// the float quotient is beyond 2^53, where isFloat64AnInteger gives up
+	assert.False(t, isFloat64AnInteger(float64(1<<62)/2))
+	assert.True(t, isMultipleOf(float64(1<<62), 2))
+	assert.False(t, isMultipleOf(float64(1<<62), 3))
+	assert.True(t, isMultipleOf(float64(1<<62), 1<<40))
+	assert.True(t, isMultipleOf(-float64(1<<63), 1<<40))
+	assert.True(t, isMultipleOf(1e300, 2))
+	assert.False(t, isMultipleOf(1e300, 7))
+	assert.True(t, isMultipleOf(1e300, 1e299))
+
+	// fractional values or divisors
+	assert.True(t, isMultipleOf(1.5, 0.5))
+	assert.False(t, isMultipleOf(1.25, 0.5))
+	assert.False(t, isMultipleOf(7.5, 5))
+}
+
+func TestEditDistance(t *testing.T) {
+
+	assert.Equal(t, 0, editDistance("", ""))
+	assert.Equal(t, 3, editDistance("", "abc"))
+	assert.Equal(t, 3, editDistance("abc", ""))
+	assert.Equal(t, 0, editDistance("active", "active"))
+	assert.Equal(t, 1, editDistance("activ", "active"))
+	assert.Equal(t, 1, editDistance("actove", "active"))
+	assert.Equal(t, 2, editDistance("acitve", "active"))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+	// runes, not bytes
+	assert.Equal(t, 1, editDistance("é", "e"))
+}
+
+func TestDecimalPlaces(t *testing.T) {
+
+	assert.Equal(t, 0, decimalPlaces(0))
+	assert.Equal(t, 0, decimalPlaces(100))
+	assert.Equal(t, 0, decimalPlaces(-1e20))
+	assert.Equal(t, 1, decimalPlaces(0.1))
+	assert.Equal(t, 2, decimalPlaces(19.99))
+	assert.Equal(t, 2, decimalPlaces(-0.05))
+	assert.Equal(t, 3, decimalPlaces(1.001))
+	assert.Equal(t, 7, decimalPlaces(1e-7))
+	// as floats, 0.1 + 0.2 is 0.30000000000000004
+	tenth := 0.1
+	assert.Equal(t, 17, decimalPlaces(tenth+0.2))
+
+	for text, decimals := range map[string]int{
+		"0": 0, "-12": 0, "19.99": 2, "19.990": 2, "1.5e-3": 4, "1.5E+1": 0, "1999e-2": 2,
+		"100e-2": 0, "0.1000000000000000001": 19, "12345678901234567.891": 3, "-0.05": 2,
+	} {
+		assert.Equal(t, decimals, decimalPlacesOfText(text), text)
+	}
+}
+
+type testColor string
+
+type testPoint struct{ x, y int }
+
+func (p testPoint) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d,%d", p.x, p.y)), nil
+}
+
+func TestConvertDocumentNode(t *testing.T) {
+
+	converted, err := convertDocumentNode(map[int]interface{}{1: "a", -2: []interface{}{map[uint8]interface{}{3: true}}})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"1": "a", "-2": []interface{}{map[string]interface{}{"3": true}}}, converted)
+
+	converted, err = convertDocumentNode(map[testColor]interface{}{"red": 1.0})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"red": 1.0}, converted)
+
+	converted, err = convertDocumentNode(map[interface{}]interface{}{"a": nil, 2: nil, testPoint{1, 2}: nil})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"a": nil, "2": nil, "1,2": nil}, converted)
+
+	_, err = convertDocumentNode(map[float64]interface{}{1.5: "a"})
+	assert.EqualError(t, err, `Map key 1.5 of type float64 cannot be a property name`)
+	_, err = convertDocumentNode([]interface{}{map[interface{}]interface{}{nil: 1}})
+	assert.EqualError(t, err, `Map key <nil> of type interface {} cannot be a property name`)
+
+	// other nodes are left as is
+	converted, err = convertDocumentNode("a")
+	assert.Nil(t, err)
+	assert.Equal(t, "a", converted)
+}
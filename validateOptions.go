@@ -0,0 +1,295 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      Options altering the behavior of the validation phase.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"errors"
+	"time"
+)
+
+// ValidateOptions holds the settings of a single validation.
+// The zero value validates strictly according to the schema.
+type ValidateOptions struct {
+
+	// Schema used to validate the properties of an object that are neither
+	// declared in "properties" nor matched by "patternProperties", when the
+	// subSchema has no "additionalProperties" keyword of its own.
+	FallbackAdditionalSchema *Schema
+
+	// Keeps the decoded document in the Result, see Result.Document.
+	CaptureDocument bool
+
+	// Scrubs the captured document before it is stored in the Result.
+	// It is called once the validation is over and may modify the document
+	// in place or return a different value.
+	RedactDocument func(document interface{}) interface{}
+
+	// Treats properties whose value is an empty string as absent when checking
+	// "required", so {"name": ""} fails {"required": ["name"]}, and lets empty
+	// strings pass "format" and x-anyFormat, so that an optional email may be
+	// left empty. These are the only keywords affected : the empty string is
+	// still checked by the others, such as minLength or pattern.
+	TreatEmptyStringAsAbsent bool
+
+	// Notified while the document is validated, see Observer.
+	Observer Observer
+
+	// Unit in which minLength and maxLength measure strings, runes by default.
+	LengthUnit LengthUnit
+
+	// Unicode normal form strings are put in before their length, pattern,
+	// format and content are checked, so that precomposed and decomposed
+	// accented characters count and match alike. Strings are left as is by
+	// default. The errors hold the strings of the document, not normalized.
+	NormalizeUnicode UnicodeNormalization
+
+	// Rejects null wherever the subSchema does not explicitly allow it with its
+	// type or its enum, even when it has no type. Nodes that are not validated by
+	// any subSchema, like undeclared properties, are not checked.
+	RejectNull bool
+
+	// Accepts the strings holding a JSON number where the subSchema is typed as
+	// a number or an integer, but not as a string, as sent by the APIs that
+	// encode large integers as strings, ex "9007199254740993". The number is
+	// validated in place of the string, with the precision of a float64, but
+	// whether it is an integer is told by its digits.
+	NumericStrings bool
+
+	// Matches the properties of the objects to those declared in "properties"
+	// and "required" regardless of case, so that "UserId" is validated as the
+	// declared "userId" and is not an additional property. When declared
+	// properties differ only by case, as "id" and "ID", a key matches the one
+	// spelled as it is, otherwise the first one in byte order, "ID".
+	CaseInsensitiveProperties bool
+
+	// Skips the "required" keyword, so that incomplete objects, like the
+	// partially filled steps of a form, can be validated. The properties that
+	// are present are still validated against their subSchemas.
+	IgnoreRequired bool
+
+	// Makes the nodes that are not objects fail the subSchemas having a
+	// "required" but no "type", while "required" only applies to objects
+	// otherwise. This catches the subSchemas lacking "type": "object". The error
+	// is a type error, with the required properties in its details.
+	StrictRequired bool
+
+	// Keeps at most this number of errors for each path of the document,
+	// the first ones reported. 0 keeps all the errors.
+	// The cap applies to the final result, once the best anyOf and oneOf
+	// branches are chosen, and is ignored with IsValid.
+	MaxErrorsPerPath int
+
+	// Checks that strings are encoded as their contentEncoding tells, for
+	// "base64", and that the content is JSON when contentMediaType is
+	// "application/json". Decoding every such string can be expensive, so these
+	// keywords are annotations only by default.
+	ValidateContent bool
+
+	// Sets ResultError.Title to the title of the subSchema of the failing
+	// field, when it has one, so errors can name fields the way the schema
+	// describes them. ResultError.String then shows the title instead of the path.
+	UseTitleInErrors bool
+
+	// Sets ResultError.Line and ResultError.Column to the position of the
+	// failing field in the source of the document, or of its closest ancestor
+	// when the field is missing. Only documents given by NewStringLoader have a
+	// source, the positions are left to 0 for the other loaders.
+	TrackPositions bool
+
+	// Records the subSchemas matched by the document, see Result.CoveredSchemas.
+	TrackCoverage bool
+
+	// Times the validation functions and the costliest keywords, see
+	// Result.Profile.
+	Profile bool
+
+	// Records the most specific subSchema each node is valid against, see
+	// Result.AppliedSchema. Ignored with IsValid, the paths of the nodes not
+	// being tracked.
+	TrackAppliedSchemas bool
+
+	// Records the properties of the objects that were evaluated by a subSchema,
+	// see Result.EvaluatedProperties. Ignored with IsValid, the paths of the
+	// objects not being tracked.
+	TrackEvaluatedProperties bool
+
+	// Skips the costliest keywords for a quick first pass, the document being
+	// validated in full afterwards : pattern, format, x-anyFormat, uniqueItems,
+	// anyOf, oneOf with its x-discriminator, allOf and not. type, required,
+	// properties and the other keywords, including $ref, enum and the custom
+	// keywords, are still checked, so a document failing the first pass fails
+	// the full validation too.
+	StructuralOnly bool
+
+	// Makes the strings that do not match their format errors. By default
+	// "format" is an annotation, the mismatches are reported by
+	// Result.Annotations and the document stays valid.
+	StrictFormat bool
+
+	// Replaces the objects and arrays held by ResultError.Value by an
+	// ElidedValue, their JSON pointer and size, so that the errors stay small
+	// when they are about large parts of the document.
+	// ResultError.ResolveValue gets the values back from the document.
+	ElideValues bool
+
+	// Largest difference between a number and a number of its enum for them to
+	// be equal, so that computed floats match, ex 0.1+0.2 and 0.3. The numbers
+	// nested in the arrays and objects of the enum are compared exactly, as are
+	// all numbers by default.
+	NumericEpsilon float64
+
+	// Suggests, for the strings not in their enum, the closest string of the
+	// enum when it is at most ENUM_SUGGESTION_MAX_DISTANCE edits away and the
+	// edits do not make up half of the string, ex "active" for "activ".
+	// The suggestion is put in ResultError.Details, under "suggestion".
+	SuggestEnum bool
+
+	// Whether the properties of an object are validated when the object itself
+	// fails. By default, DESCENT_UNLESS_MISTYPED, they are not when the object
+	// is not of the type of the subSchema, and are otherwise.
+	PropertyDescent PropertyDescent
+
+	// What the requirement of the errors of additionalProperties false tells
+	// of the allowed properties. By default, ALLOWED_PROPERTIES_COUNT, only how
+	// many properties and patternProperties there are, as listing them all
+	// makes heavy errors for the objects declaring hundreds of properties.
+	AdditionalPropertyErrorDetail AdditionalPropertyErrorDetail
+
+	// Rejects the empty documents before they are validated, whatever the
+	// schema allows : null, the empty string, and the blank sources of
+	// NewStringLoader. ValidateWithOptions then returns ErrEmptyDocument.
+	DisallowEmpty bool
+
+	// Stops the validation once this time is passed, the clock being read
+	// every few hundred nodes. ValidateWithOptions then returns the errors
+	// found so far along with ErrDeadlineExceeded. The zero value sets no
+	// deadline.
+	Deadline time.Time
+
+	// Only the validity of the document matters : the path of the nodes is not
+	// tracked, which saves an allocation per node. The errors are still
+	// reported but their Context is nil, and so is the context given to
+	// Observer.OnEnter.
+	IsValid bool
+
+	// Keys of the objects of the document in the order of its JSON text, by
+	// the address of their map, see x-propertyOrder. Set by loadDocument.
+	keyOrders map[uintptr][]string
+
+	// Numbers of the document as written in its JSON text, by JSON pointer, see
+	// x-maxDecimals. Set by loadDocument.
+	numberTexts map[string]string
+
+	// Tells that the scores of the results are not kept, as no anyOf nor oneOf
+	// compares them. Set by loadDocument.
+	unscored bool
+}
+
+// ErrEmptyDocument is returned, without result, for the empty documents when
+// ValidateOptions.DisallowEmpty is set
+var ErrEmptyDocument = errors.New(ERROR_MESSAGE_EMPTY_DOCUMENT)
+
+// ErrDeadlineExceeded is returned with a partial result when the validation
+// did not end before ValidateOptions.Deadline
+var ErrDeadlineExceeded = errors.New(ERROR_MESSAGE_DEADLINE_EXCEEDED)
+
+// Number of nodes validated between two readings of the clock
+const deadlineCheckInterval = 256
+
+// Tracks ValidateOptions.Deadline, shared by the sub results
+type deadlineCheck struct {
+	deadline time.Time
+	nodes    int
+	exceeded bool
+}
+
+// Tells whether the deadline is passed, counting a validated node
+func (d *deadlineCheck) isExceeded() bool {
+	if d == nil {
+		return false
+	}
+	if !d.exceeded && d.nodes%deadlineCheckInterval == 0 {
+		d.exceeded = time.Now().After(d.deadline)
+	}
+	d.nodes++
+	return d.exceeded
+}
+
+// LengthUnit is the unit in which the length of a string is measured
+type LengthUnit int
+
+const (
+	LENGTH_IN_RUNES LengthUnit = iota // unicode code points
+	LENGTH_IN_BYTES                   // bytes of the UTF-8 encoding
+)
+
+// UnicodeNormalization is a Unicode normal form, see ValidateOptions.NormalizeUnicode
+type UnicodeNormalization int
+
+const (
+	NORMALIZE_NONE UnicodeNormalization = iota
+	NORMALIZE_NFC                       // canonical composition, é is a single code point
+	NORMALIZE_NFD                       // canonical decomposition, é is e followed by a combining accent
+)
+
+// PropertyDescent tells when the properties of a failing object are validated,
+// see ValidateOptions.PropertyDescent
+type PropertyDescent int
+
+const (
+	// skips the properties of the objects failing their type only
+	DESCENT_UNLESS_MISTYPED PropertyDescent = iota
+	// validates the properties whatever the object fails, for complete errors
+	DESCENT_ALWAYS
+	// skips the properties of the objects failing their type, their number of
+	// properties, their required properties or propertyNames, for less noise
+	DESCENT_ON_VALID_OBJECT
+)
+
+// AdditionalPropertyErrorDetail tells what the additionalProperties errors
+// report of the allowed properties, see ValidateOptions.AdditionalPropertyErrorDetail
+type AdditionalPropertyErrorDetail int
+
+const (
+	// the numbers of properties and patternProperties, ex map[patternProperties:1 properties:2]
+	ALLOWED_PROPERTIES_COUNT AdditionalPropertyErrorDetail = iota
+	// nothing, the requirement is nil
+	ALLOWED_PROPERTIES_NONE
+	// the sorted names of properties and keys of patternProperties, ex map[patternProperties:[^x-] properties:[a b]]
+	ALLOWED_PROPERTIES_FULL
+)
+
+// Observer is notified of the progress of a validation, for instrumentation.
+type Observer interface {
+
+	// Called each time a node of the document is validated against a subSchema.
+	// schemaLocation is a JSON reference to the subSchema, ex #/properties/a
+	OnEnter(context *JSONContext, schemaLocation string)
+
+	// Called for each error, including the errors of the anyOf and oneOf
+	// subSchemas that are not kept in the final result.
+	OnError(resultError ResultError)
+}
@@ -26,10 +26,19 @@
 package gojsonschema
 
 import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
 	"reflect"
 	"regexp"
 	"strconv"
+	"strings"
+	"time"
 	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
 )
 
 func Validate(ls JSONLoader, ld JSONLoader) (*Result, error) {
@@ -49,40 +58,373 @@ func Validate(ls JSONLoader, ld JSONLoader) (*Result, error) {
 
 }
 
+func ValidateWithOptions(ls JSONLoader, ld JSONLoader, options ValidateOptions) (*Result, error) {
+
+	schema, err := NewSchema(ls)
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.ValidateWithOptions(ld, options)
+
+}
+
+// Validates a document against the schema found at schemaURL, an http(s) or file URL.
+// Failures to fetch or parse the schema are returned as a *SchemaLoadError,
+// which sets them apart from the errors about the document itself.
+func ValidateURL(schemaURL string, ld JSONLoader) (*Result, error) {
+
+	schema, err := NewSchema(NewReferenceLoader(schemaURL))
+	if err != nil {
+		return nil, &SchemaLoadError{URL: schemaURL, Err: err}
+	}
+
+	return schema.Validate(ld)
+
+}
+
+// SchemaLoadError is returned by ValidateURL when the schema cannot be loaded
+type SchemaLoadError struct {
+	URL string
+	Err error
+}
+
+func (e *SchemaLoadError) Error() string {
+	return fmt.Sprintf(ERROR_MESSAGE_SCHEMA_LOAD_X, e.URL, e.Err)
+}
+
 func (v *Schema) Validate(l JSONLoader) (*Result, error) {
+	return v.ValidateWithOptions(l, ValidateOptions{})
+}
+
+// ValidateFirstError validates a document and returns the message of its most
+// significant error, see ResultErrors.First, "" when it is valid
+func (v *Schema) ValidateFirstError(l JSONLoader) (string, error) {
+
+	result, err := v.Validate(l)
+	if err != nil {
+		return "", err
+	}
+
+	if first, ok := result.Errors().First(); ok {
+		return first.String(), nil
+	}
+
+	return "", nil
+}
+
+func (v *Schema) ValidateWithOptions(l JSONLoader, options ValidateOptions) (*Result, error) {
 
 	// load document
 
-	root, err := l.loadJSON()
+	if options.DisallowEmpty {
+		if stringLoader, ok := l.(*jsonStringLoader); ok && strings.TrimSpace(stringLoader.source) == "" {
+			return nil, ErrEmptyDocument
+		}
+	}
+
+	root, err := loadDocument(l, &options, v)
 	if err != nil {
 		return nil, err
 	}
 
+	if options.DisallowEmpty && (root == nil || root == "") {
+		return nil, ErrEmptyDocument
+	}
+
 	// begin validation
 
-	result := &Result{}
-	context := NewJSONContext(STRING_CONTEXT_ROOT, nil)
-	v.rootSchema.validateRecursive(v.rootSchema, root, result, context)
+	result := v.validateDocument(root, options)
+
+	if result.deadline != nil && result.deadline.exceeded {
+		return result, ErrDeadlineExceeded
+	}
+
+	if options.TrackPositions && !options.IsValid {
+		if stringLoader, ok := l.(*jsonStringLoader); ok {
+			if err := result.setErrorsPosition(stringLoader.source); err != nil {
+				return nil, err
+			}
+		}
+	}
 
 	return result, nil
 
 }
 
-func (v *subSchema) subValidateWithContext(document interface{}, context *JSONContext) *Result {
-	result := &Result{}
-	v.validateRecursive(v, document, result, context)
+// ValidateInto validates the document and, only when it is valid, unmarshals
+// it into out with encoding/json. The document is loaded once : the JSON text
+// of a NewStringLoader is decoded both for the validation and into out.
+// An invalid document leaves out untouched, the result telling why.
+func (v *Schema) ValidateInto(l JSONLoader, out interface{}) (*Result, error) {
+
+	var options ValidateOptions
+	root, err := loadDocument(l, &options, v)
+	if err != nil {
+		return nil, err
+	}
+
+	result := v.validateDocument(root, options)
+	if !result.Valid() {
+		return result, nil
+	}
+
+	data, err := documentBytes(l, root)
+	if err != nil {
+		return result, err
+	}
+
+	return result, json.Unmarshal(data, out)
+}
+
+// ValidateWithOverrides validates a document as if some subSchemas of the schema
+// were replaced by other schemas, ex to compare the outcomes of two versions of
+// a definition. The overrides are keyed by the JSON pointers of the subSchemas
+// they replace in the schema, ex "#/definitions/address", and apply where a
+// $ref points to these subSchemas. The schema itself is left untouched.
+func (v *Schema) ValidateWithOverrides(l JSONLoader, overrides map[string]*Schema) (*Result, error) {
+
+	schemas := []*Schema{v}
+	replaced := make(map[string]*subSchema, len(overrides))
+	for pointer, override := range overrides {
+		s := v.subSchemaAt(pointer)
+		if s == nil {
+			return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_NO_SUBSCHEMA_AT_X, pointer))
+		}
+		if override == nil {
+			return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_NO_OVERRIDE_FOR_X, pointer))
+		}
+		replaced[s.location] = override.rootSchema
+		schemas = append(schemas, override)
+	}
+
+	var options ValidateOptions
+	root, err := loadDocument(l, &options, schemas...)
+	if err != nil {
+		return nil, err
+	}
+
+	return validateRoot(v.rootSchema, root, options, nil, replaced), nil
+}
+
+// ValidateKeyword validates a value against a single keyword, as a schema
+// holding only this keyword does, so that constraints can be tested on their
+// own, ex ValidateKeyword("maximum", 10, 12). The extensions are enabled, and
+// format and the content keywords are checked as the StrictFormat and
+// ValidateContent options do. An error is returned for the keywords that report
+// no errors of their own, like "properties", and for invalid requirements.
+func ValidateKeyword(keyword string, requirement interface{}, value interface{}) (*Result, error) {
+
+	if _, ok := keywordErrors[keyword]; !ok {
+		return nil, errors.New(fmt.Sprintf(ERROR_MESSAGE_UNKNOWN_KEYWORD_X, keyword))
+	}
+
+	schema, err := NewSchemaWithOptions(NewGoLoader(map[string]interface{}{keyword: requirement}), SchemaLoaderOptions{EnableExtensions: true})
+	if err != nil {
+		return nil, err
+	}
+
+	return schema.ValidateWithOptions(NewGoLoader(value), ValidateOptions{StrictFormat: true, ValidateContent: true})
+}
+
+// Validates a document against all the given schemas, as if they were
+// the subSchemas of an allOf.
+func ValidateAllOf(schemas []*Schema, l JSONLoader) (*Result, error) {
+
+	var options ValidateOptions
+	root, err := loadDocument(l, &options, schemas...)
+	if err != nil {
+		return nil, err
+	}
+
+	allOf := &subSchema{property: STRING_ROOT_SCHEMA_PROPERTY}
+	for _, schema := range schemas {
+		allOf.AddAllOf(schema.rootSchema)
+	}
+
+	return validateRoot(allOf, root, options, nil, nil), nil
+
+}
+
+// Loads a document to validate against the given schemas, setting in options
+// what their keywords need of its JSON text, which only a NewStringLoader
+// keeps : the order of the keys for x-propertyOrder, the numbers as written
+// for x-maxDecimals. The scores are not kept when none of the schemas has
+// anyOf nor oneOf.
+func loadDocument(l JSONLoader, options *ValidateOptions, schemas ...*Schema) (interface{}, error) {
+
+	if options.FallbackAdditionalSchema != nil {
+		schemas = append(schemas, options.FallbackAdditionalSchema)
+	}
+	var propertyOrdered, decimalsBounded, combined bool
+	for _, schema := range schemas {
+		propertyOrdered = propertyOrdered || schema.propertyOrdered
+		decimalsBounded = decimalsBounded || schema.decimalsBounded
+		combined = combined || schema.combined
+	}
+	options.unscored = !combined
+
+	stringLoader, ok := l.(*jsonStringLoader)
+	if !ok {
+		return l.loadJSON()
+	}
+
+	root, err := l.loadJSON()
+	if err != nil {
+		return nil, err
+	}
+
+	// the decoded maps lose the order of the keys
+	if propertyOrdered {
+		if root, options.keyOrders, err = decodeWithKeyOrders([]byte(stringLoader.source)); err != nil {
+			return nil, err
+		}
+	}
+
+	// the decoded floats lose the digits of the numbers
+	if decimalsBounded && !options.IsValid {
+		if options.numberTexts, err = decodeNumberTexts([]byte(stringLoader.source)); err != nil {
+			return nil, err
+		}
+	}
+
+	return root, nil
+}
+
+// Validates an already loaded document
+func (v *Schema) validateDocument(root interface{}, options ValidateOptions) *Result {
+	return validateRoot(v.rootSchema, root, options, nil, nil)
+}
+
+// Validates a document, only its changes when they are given, the subSchemas
+// that $ref point to being replaced by their overrides, by location
+func validateRoot(rootSchema *subSchema, root interface{}, options ValidateOptions, changes *changedPaths, overrides map[string]*subSchema) *Result {
+
+	result := &Result{options: &options, changes: changes, overrides: overrides}
+	if options.TrackCoverage {
+		result.coverage = make(map[string]bool)
+	}
+	if options.Profile {
+		result.profile = make(map[string]time.Duration)
+	}
+	if options.TrackAppliedSchemas && !options.IsValid {
+		result.applied = make(map[string]*subSchema)
+	}
+	if options.TrackEvaluatedProperties && !options.IsValid {
+		result.evaluated = make(map[string]map[string]bool)
+	}
+	if !options.Deadline.IsZero() {
+		result.deadline = &deadlineCheck{deadline: options.Deadline}
+	}
+	context := result.newContext(STRING_CONTEXT_ROOT, nil)
+	rootSchema.validateRecursive(rootSchema, root, nil, result, context)
+
+	// overlapping subSchemas ( allOf, dependencies... ) may report the same error
+	// several times, the paths are needed to tell the errors apart
+	if !options.IsValid {
+		result.errors = ResultErrors(result.errors).Dedup()
+		if options.MaxErrorsPerPath > 0 {
+			result.errors = ResultErrors(result.errors).limitPerContext(options.MaxErrorsPerPath)
+		}
+	}
+
+	if options.ElideValues {
+		ResultErrors(result.errors).elideValues(root)
+		ResultErrors(result.annotations).elideValues(root)
+	}
+
+	if options.CaptureDocument {
+		if options.RedactDocument != nil {
+			root = options.RedactDocument(root)
+		}
+		result.document = root
+	}
+
+	return result
+
+}
+
+func (v *subSchema) subValidateWithContext(document interface{}, parentNode interface{}, context *JSONContext, parent *Result) *Result {
+	return v.subValidateInto(parent.newSubResult(), document, parentNode, context)
+}
+
+// Validates the whole node, including the parts that did not change when
+// only the changes of the document are validated, see Schema.ValidatePatched
+func (v *subSchema) subValidateInFull(document interface{}, parentNode interface{}, context *JSONContext, parent *Result) *Result {
+	result := parent.newSubResult()
+	result.changes = nil
+	return v.subValidateInto(result, document, parentNode, context)
+}
+
+func (v *subSchema) subValidateInto(result *Result, document interface{}, parentNode interface{}, context *JSONContext) *Result {
+	v.validateRecursive(v, document, parentNode, result, context)
+	if result.options.UseTitleInErrors {
+		if title := v.resolvedTitle(); title != nil {
+			result.setErrorsTitle(*title, context, 0)
+		}
+	}
 	return result
 }
 
 // Walker function to validate the json recursively against the subSchema
-func (v *subSchema) validateRecursive(currentSubSchema *subSchema, currentNode interface{}, result *Result, context *JSONContext) {
+// parentNode is the object or array holding currentNode, nil at the root
+func (v *subSchema) validateRecursive(currentSubSchema *subSchema, currentNode interface{}, parentNode interface{}, result *Result, context *JSONContext) {
 
 	internalLog("validateRecursive %s", context.String())
 	internalLog(" %v", currentNode)
 
+	if result.deadline.isExceeded() {
+		return
+	}
+
+	if result.options.Observer != nil {
+		result.options.Observer.OnEnter(context, currentSubSchema.location)
+	}
+
+	if result.coverage != nil {
+		nbErrorsBefore := len(result.errors)
+		defer func() {
+			if len(result.errors) == nbErrorsBefore {
+				result.coverage[currentSubSchema.location] = true
+			}
+		}()
+	}
+
+	if currentSubSchema.errorMessage != nil || currentSubSchema.errorMessages != nil {
+		nbErrorsBefore := len(result.errors)
+		defer func() {
+			result.setErrorsMessage(currentSubSchema, context, nbErrorsBefore)
+		}()
+	}
+
+	if result.applied != nil {
+		pointer := context.Pointer()
+		nbErrorsBefore := len(result.errors)
+		before, hadBefore := result.applied[pointer]
+		defer func() {
+			after := result.applied[pointer]
+			switch {
+			case len(result.errors) != nbErrorsBefore && after != before:
+				// the subSchemas nested in a failing one do not apply
+				if hadBefore {
+					result.applied[pointer] = before
+				} else {
+					delete(result.applied, pointer)
+				}
+			case len(result.errors) == nbErrorsBefore && after == before:
+				// unless a nested subSchema applied, as a $ref or an allOf one
+				result.applied[pointer] = currentSubSchema
+			}
+		}()
+	}
+
 	// Handle referenced schemas, returns directly when a $ref is found
 	if currentSubSchema.refSchema != nil {
-		v.validateRecursive(currentSubSchema.refSchema, currentNode, result, context)
+		refSchema := currentSubSchema.refSchema
+		if override, ok := result.overrides[refSchema.location]; ok {
+			refSchema = override
+		}
+		v.validateRecursive(refSchema, currentNode, parentNode, result, context)
 		return
 	}
 
@@ -90,20 +432,40 @@ func (v *subSchema) validateRecursive(currentSubSchema *subSchema, currentNode i
 	if currentNode == nil {
 
 		if currentSubSchema.types.IsTyped() && !currentSubSchema.types.Contains(TYPE_NULL) {
-			result.AddError(
+			result.addError(
 				context,
 				KEY_TYPE,
 				currentSubSchema.types.String(),
 				currentNode,
+				typeErrorDetails(currentSubSchema.types.types, currentNode),
 			)
 			return
 		}
 
-		currentSubSchema.validateSchema(currentSubSchema, currentNode, result, context)
+		if result.options.RejectNull && !currentSubSchema.allowsNull() {
+			result.addError(
+				context,
+				KEY_TYPE,
+				STRING_NOT_NULL,
+				currentNode,
+				typeErrorDetails(currentSubSchema.types.types, currentNode),
+			)
+			return
+		}
+
+		currentSubSchema.validateSchema(currentSubSchema, currentNode, parentNode, result, context)
 		v.validateCommon(currentSubSchema, currentNode, result, context)
 
 	} else { // Not a null value
 
+		// numbers sent as strings, see ValidateOptions.NumericStrings
+		integerString := false
+		if s, ok := currentNode.(string); ok && result.options.NumericStrings && currentSubSchema.expectsNumber() {
+			if f, isInteger, ok := parseJSONNumber(s); ok {
+				currentNode, integerString = f, isInteger
+			}
+		}
+
 		rValue := reflect.ValueOf(currentNode)
 		rKind := rValue.Kind()
 
@@ -114,18 +476,19 @@ func (v *subSchema) validateRecursive(currentSubSchema *subSchema, currentNode i
 		case reflect.Slice:
 
 			if currentSubSchema.types.IsTyped() && !currentSubSchema.types.Contains(TYPE_ARRAY) {
-				result.AddError(
+				result.addError(
 					context,
 					KEY_TYPE,
 					currentSubSchema.types.String(),
 					currentNode,
+					typeErrorDetails(currentSubSchema.types.types, currentNode),
 				)
 				return
 			}
 
 			castCurrentNode := currentNode.([]interface{})
 
-			currentSubSchema.validateSchema(currentSubSchema, castCurrentNode, result, context)
+			currentSubSchema.validateSchema(currentSubSchema, castCurrentNode, parentNode, result, context)
 
 			v.validateArray(currentSubSchema, castCurrentNode, result, context)
 			v.validateCommon(currentSubSchema, castCurrentNode, result, context)
@@ -134,30 +497,81 @@ func (v *subSchema) validateRecursive(currentSubSchema *subSchema, currentNode i
 
 		case reflect.Map:
 			if currentSubSchema.types.IsTyped() && !currentSubSchema.types.Contains(TYPE_OBJECT) {
-				result.AddError(
+				result.addError(
 					context,
 					KEY_TYPE,
 					currentSubSchema.types.String(),
 					currentNode,
+					typeErrorDetails(currentSubSchema.types.types, currentNode),
 				)
-				return
+				if result.options.PropertyDescent != DESCENT_ALWAYS {
+					return
+				}
 			}
 
 			castCurrentNode, ok := currentNode.(map[string]interface{})
 			if !ok {
-				castCurrentNode = convertDocumentNode(currentNode).(map[string]interface{})
+				converted, err := convertDocumentNode(currentNode)
+				if err != nil {
+					// not a JSON object, whose keys are strings
+					result.addError(
+						context,
+						KEY_TYPE,
+						err.Error(),
+						currentNode,
+						map[string]interface{}{STRING_EXPECTED: []string{TYPE_OBJECT}, STRING_ACTUAL: fmt.Sprintf("%T", currentNode)},
+					)
+					return
+				}
+				castCurrentNode = converted.(map[string]interface{})
 			}
 
-			currentSubSchema.validateSchema(currentSubSchema, castCurrentNode, result, context)
+			currentSubSchema.validateSchema(currentSubSchema, castCurrentNode, parentNode, result, context)
 
-			v.validateObject(currentSubSchema, castCurrentNode, result, context)
+			objectValid := v.validateObject(currentSubSchema, castCurrentNode, result, context)
 			v.validateCommon(currentSubSchema, castCurrentNode, result, context)
 
+			if !objectValid && result.options.PropertyDescent == DESCENT_ON_VALID_OBJECT {
+				break
+			}
+
 			for _, pSchema := range currentSubSchema.propertiesChildren {
 				nextNode, ok := castCurrentNode[pSchema.property]
 				if ok {
-					subContext := NewJSONContext(pSchema.property, context)
-					v.validateRecursive(pSchema, nextNode, result, subContext)
+					result.evaluateProperty(context, pSchema.property)
+					if result.skips(pSchema.property, context) {
+						continue
+					}
+					subContext := result.newContext(pSchema.property, context)
+					scoreBefore, nbErrorsBefore := result.score, len(result.errors)
+					v.validateRecursive(pSchema, nextNode, castCurrentNode, result, subContext)
+					if result.options.UseTitleInErrors {
+						if title := pSchema.resolvedTitle(); title != nil {
+							result.setErrorsTitle(*title, subContext, nbErrorsBefore)
+						}
+					}
+					result.scoreProperty(scoreBefore, nbErrorsBefore)
+				}
+			}
+
+			// the keys spelled differently from their declared property
+			if result.options.CaseInsensitiveProperties {
+				for _, key := range sortedKeys(castCurrentNode) {
+					if currentSubSchema.hasPropertyChild(key) || result.skips(key, context) {
+						continue
+					}
+					if pSchema := currentSubSchema.propertyChildFold(key); pSchema != nil {
+						result.evaluateProperty(context, key)
+						subContext := result.newContext(key, context)
+						scoreBefore, nbErrorsBefore := result.score, len(result.errors)
+						v.validateRecursive(pSchema, castCurrentNode[key], castCurrentNode, result, subContext)
+						if result.options.UseTitleInErrors {
+							if title := pSchema.resolvedTitle(); title != nil {
+								result.setErrorsTitle(*title, subContext, nbErrorsBefore)
+							}
+						}
+						result.scoreProperty(scoreBefore, nbErrorsBefore)
+					}
 				}
 			}
 
@@ -166,18 +580,19 @@ func (v *subSchema) validateRecursive(currentSubSchema *subSchema, currentNode i
 		case reflect.Bool:
 
 			if currentSubSchema.types.IsTyped() && !currentSubSchema.types.Contains(TYPE_BOOLEAN) {
-				result.AddError(
+				result.addError(
 					context,
 					KEY_TYPE,
 					currentSubSchema.types.String(),
 					currentNode,
+					typeErrorDetails(currentSubSchema.types.types, currentNode),
 				)
 				return
 			}
 
 			value := currentNode.(bool)
 
-			currentSubSchema.validateSchema(currentSubSchema, value, result, context)
+			currentSubSchema.validateSchema(currentSubSchema, value, parentNode, result, context)
 			v.validateNumber(currentSubSchema, value, result, context)
 			v.validateCommon(currentSubSchema, value, result, context)
 			v.validateString(currentSubSchema, value, result, context)
@@ -185,18 +600,19 @@ func (v *subSchema) validateRecursive(currentSubSchema *subSchema, currentNode i
 		case reflect.String:
 
 			if currentSubSchema.types.IsTyped() && !currentSubSchema.types.Contains(TYPE_STRING) {
-				result.AddError(
+				result.addError(
 					context,
 					KEY_TYPE,
 					currentSubSchema.types.String(),
 					currentNode,
+					typeErrorDetails(currentSubSchema.types.types, currentNode),
 				)
 				return
 			}
 
 			value := currentNode.(string)
 
-			currentSubSchema.validateSchema(currentSubSchema, value, result, context)
+			currentSubSchema.validateSchema(currentSubSchema, value, parentNode, result, context)
 			v.validateNumber(currentSubSchema, value, result, context)
 			v.validateCommon(currentSubSchema, value, result, context)
 			v.validateString(currentSubSchema, value, result, context)
@@ -205,39 +621,86 @@ func (v *subSchema) validateRecursive(currentSubSchema *subSchema, currentNode i
 
 			value := currentNode.(float64)
 
+			// NaN and infinities are not JSON numbers, whatever the subSchema.
+			// Decoding JSON never gives them, but already decoded Go values can
+			if math.IsNaN(value) || math.IsInf(value, 0) {
+				result.addError(
+					context,
+					KEY_TYPE,
+					STRING_FINITE_NUMBER,
+					currentNode,
+					typeErrorDetails(currentSubSchema.types.types, currentNode),
+				)
+				return
+			}
+
 			// Note: JSON only understand one kind of numeric ( can be float or int )
 			// JSON subSchema make a distinction between fload and int
 			// An integer can be a number, but a number ( with decimals ) cannot be an integer
-			isInteger := isFloat64AnInteger(value)
+			isInteger := integerString || isFloat64AnInteger(value)
 			validType := currentSubSchema.types.Contains(TYPE_NUMBER) || (isInteger && currentSubSchema.types.Contains(TYPE_INTEGER))
 
 			if currentSubSchema.types.IsTyped() && !validType {
-				result.AddError(
+				result.addError(
 					context,
 					KEY_TYPE,
 					currentSubSchema.types.String(),
 					currentNode,
+					typeErrorDetails(currentSubSchema.types.types, currentNode),
 				)
 				return
 			}
 
-			currentSubSchema.validateSchema(currentSubSchema, value, result, context)
+			currentSubSchema.validateSchema(currentSubSchema, value, parentNode, result, context)
 			v.validateNumber(currentSubSchema, value, result, context)
 			v.validateCommon(currentSubSchema, value, result, context)
 			v.validateString(currentSubSchema, value, result, context)
 		}
 	}
 
+	v.validateKeywords(currentSubSchema, currentNode, parentNode, result, context)
+
 	result.incrementScore()
 }
 
+// Details of the type errors : the types expected by the subSchema, and the
+// one of the node
+func typeErrorDetails(expected []string, node interface{}) map[string]interface{} {
+	return map[string]interface{}{
+		STRING_EXPECTED: append([]string{}, expected...),
+		STRING_ACTUAL:   jsonTypeOf(node),
+	}
+}
+
+// Runs the custom keywords of the subSchema, see KeywordValidator
+func (v *subSchema) validateKeywords(currentSubSchema *subSchema, currentNode interface{}, parentNode interface{}, result *Result, context *JSONContext) {
+
+	defer result.startTiming("validateKeywords")()
+
+	for _, keyword := range sortedKeys(currentSubSchema.keywords) {
+		stopTiming := result.startTiming(keyword)
+		err := currentSubSchema.keywords[keyword].Validate(currentNode, parentNode, context)
+		stopTiming()
+		if err != nil {
+			result.AddError(
+				context,
+				keyword,
+				err.Error(),
+				currentNode,
+			)
+		}
+	}
+}
+
 // Different kinds of validation there, subSchema / common / array / object / string...
-func (v *subSchema) validateSchema(currentSubSchema *subSchema, currentNode interface{}, result *Result, context *JSONContext) {
+func (v *subSchema) validateSchema(currentSubSchema *subSchema, currentNode interface{}, parentNode interface{}, result *Result, context *JSONContext) {
 
 	internalLog("validateSchema %s", context.String())
 	internalLog(" %v", currentNode)
+	defer result.startTiming("validateSchema")()
 
-	if len(currentSubSchema.anyOf) > 0 {
+	if len(currentSubSchema.anyOf) > 0 && !result.options.StructuralOnly {
+		stopTiming := result.startTiming(KEY_ANY_OF)
 
 		validatedAnyOf := false
 		// list of results that the best is later determined from
@@ -245,7 +708,7 @@ func (v *subSchema) validateSchema(currentSubSchema *subSchema, currentNode inte
 
 		for _, anyOfSchema := range currentSubSchema.anyOf {
 			if !validatedAnyOf {
-				validationResult := anyOfSchema.subValidateWithContext(currentNode, context)
+				validationResult := anyOfSchema.subValidateInFull(currentNode, parentNode, context, result)
 				validatedAnyOf = validationResult.Valid()
 				results = append(results, validationResult)
 			}
@@ -264,25 +727,38 @@ func (v *subSchema) validateSchema(currentSubSchema *subSchema, currentNode inte
 				)
 			}
 		}
+		stopTiming()
 	}
 
-	if len(currentSubSchema.oneOf) > 0 {
-		var results []*Result
+	if branch := currentSubSchema.discriminatedBranch(currentNode); branch != nil && !result.options.StructuralOnly {
+		// the other subSchemas are assumed not to match
+		validationResult := branch.subValidateInFull(currentNode, parentNode, context, result)
+		if !validationResult.Valid() {
+			result.mergeErrors(validationResult)
+		}
+	} else if len(currentSubSchema.oneOf) > 0 && !result.options.StructuralOnly {
+		stopTiming := result.startTiming(KEY_ONE_OF)
+		// the failing results only matter while no subSchema matched
+		var best bestResult
 		var nbValidated int
 
 		for _, oneOfSchema := range currentSubSchema.oneOf {
-			validationResult := oneOfSchema.subValidateWithContext(currentNode, context)
+			validationResult := oneOfSchema.subValidateInFull(currentNode, parentNode, context, result)
 			if validationResult.Valid() {
 				nbValidated++
-			} else {
-				results = append(results, validationResult)
+				if nbValidated > 1 {
+					// oneOf already failed, whatever the others
+					break
+				}
+			} else if nbValidated == 0 {
+				best.add(validationResult)
 			}
 		}
 
 		if nbValidated != 1 {
 			var bestValidationResult *Result
 			if nbValidated == 0 {
-				bestValidationResult = getBestResult(results)
+				bestValidationResult = best.get()
 			}
 
 			if bestValidationResult != nil {
@@ -298,13 +774,14 @@ func (v *subSchema) validateSchema(currentSubSchema *subSchema, currentNode inte
 				)
 			}
 		}
-
+		stopTiming()
 	}
 
-	if len(currentSubSchema.allOf) > 0 {
+	if len(currentSubSchema.allOf) > 0 && !result.options.StructuralOnly {
+		stopTiming := result.startTiming(KEY_ALL_OF)
 		var nbValidated int
 		for _, allOfSchema := range currentSubSchema.allOf {
-			validationResult := allOfSchema.subValidateWithContext(currentNode, context)
+			validationResult := allOfSchema.subValidateWithContext(currentNode, parentNode, context, result)
 			if validationResult.Valid() {
 				nbValidated++
 			}
@@ -319,10 +796,12 @@ func (v *subSchema) validateSchema(currentSubSchema *subSchema, currentNode inte
 				currentNode,
 			)
 		}
+		stopTiming()
 	}
 
-	if currentSubSchema.not != nil {
-		validationResult := currentSubSchema.not.subValidateWithContext(currentNode, context)
+	if currentSubSchema.not != nil && !result.options.StructuralOnly {
+		stopTiming := result.startTiming(KEY_NOT)
+		validationResult := currentSubSchema.not.subValidateInFull(currentNode, parentNode, context, result)
 		if validationResult.Valid() {
 			result.AddError(
 				context,
@@ -331,6 +810,7 @@ func (v *subSchema) validateSchema(currentSubSchema *subSchema, currentNode inte
 				currentNode,
 			)
 		}
+		stopTiming()
 	}
 
 	if currentSubSchema.dependencies != nil && len(currentSubSchema.dependencies) > 0 {
@@ -343,17 +823,18 @@ func (v *subSchema) validateSchema(currentSubSchema *subSchema, currentNode inte
 					case []string:
 						for _, dependOnKey := range dependency {
 							if _, dependencyResolved := currentNode.(map[string]interface{})[dependOnKey]; !dependencyResolved {
-								result.AddError(
-									NewJSONContext(elementKey, context),
+								result.addError(
+									result.newContext(elementKey, context),
 									KEY_DEPENDENCIES,
 									dependency,
 									currentNode,
+									map[string]interface{}{STRING_DEPENDENCY: elementKey},
 								)
 							}
 						}
 
 					case *subSchema:
-						dependency.validateRecursive(dependency, currentNode, result, context)
+						v.validateDependentSchema(dependency, elementKey, currentNode, parentNode, result, context)
 
 					}
 				}
@@ -361,17 +842,41 @@ func (v *subSchema) validateSchema(currentSubSchema *subSchema, currentNode inte
 		}
 	}
 
+	if len(currentSubSchema.dependentSchemas) > 0 {
+		if isKind(currentNode, reflect.Map) {
+			for elementKey := range currentNode.(map[string]interface{}) {
+				if dependency, ok := currentSubSchema.dependentSchemas[elementKey]; ok {
+					v.validateDependentSchema(dependency, elementKey, currentNode, parentNode, result, context)
+				}
+			}
+		}
+	}
+
 	result.incrementScore()
 }
 
+// Validates the whole object against the schema depending on one of its properties,
+// errors being scoped to this property
+func (v *subSchema) validateDependentSchema(dependency *subSchema, elementKey string, currentNode interface{}, parentNode interface{}, result *Result, context *JSONContext) {
+
+	validationResult := dependency.subValidateWithContext(currentNode, parentNode, result.newContext(elementKey, context), result)
+	validationResult.setErrorsDetail(STRING_DEPENDENCY, elementKey)
+	result.mergeErrors(validationResult)
+}
+
 func (v *subSchema) validateCommon(currentSubSchema *subSchema, value interface{}, result *Result, context *JSONContext) {
 
 	internalLog("validateCommon %s", context.String())
 	internalLog(" %v", value)
+	defer result.startTiming("validateCommon")()
 
 	// enum:
 	if len(currentSubSchema.enum) > 0 {
+		stopTiming := result.startTiming(KEY_ENUM)
 		has, err := currentSubSchema.ContainsEnum(value)
+		if err == nil && !has && result.options.NumericEpsilon > 0 {
+			has = currentSubSchema.containsEnumNumberWithin(value, result.options.NumericEpsilon)
+		}
 		if err != nil { // caused from a bad value in JSON instance
 			result.AddError(
 				context,
@@ -380,66 +885,101 @@ func (v *subSchema) validateCommon(currentSubSchema *subSchema, value interface{
 				value,
 			)
 		} else if !has {
-			result.AddError(
+			var details map[string]interface{}
+			if stringValue, ok := value.(string); ok && result.options.SuggestEnum {
+				if suggestion, ok := currentSubSchema.enumSuggestion(stringValue); ok {
+					details = map[string]interface{}{STRING_SUGGESTION: suggestion}
+				}
+			}
+			result.addError(
 				context,
 				KEY_ENUM,
 				currentSubSchema.enum,
 				value,
+				details,
 			)
 		}
+		stopTiming()
+	}
+
+	// required, when the node is not an object and the subSchema has no type:
+	if result.options.StrictRequired && len(currentSubSchema.required) > 0 && !currentSubSchema.types.IsTyped() && !isKind(value, reflect.Map) {
+		result.addError(
+			context,
+			KEY_TYPE,
+			TYPE_OBJECT,
+			value,
+			map[string]interface{}{
+				KEY_REQUIRED:    currentSubSchema.required,
+				STRING_EXPECTED: []string{TYPE_OBJECT},
+				STRING_ACTUAL:   jsonTypeOf(value),
+			},
+		)
 	}
 
 	result.incrementScore()
 }
 
+// Validates the item at index i of an array against items, or against
+// additionalItems beyond a tuple. The array is given to the subSchemas as the
+// parent of the item, nil when it is not known, see ArrayValidator.
+func (s *subSchema) validateItem(item interface{}, i int, array interface{}, result *Result, context *JSONContext) {
+
+	nbTupleItems := len(s.itemsChildren)
+
+	// additionalItems: false, the array fails once, on its first extra item
+	if !s.itemsChildrenIsSingleSchema && nbTupleItems > 0 && i == nbTupleItems && s.additionalItems == false {
+		result.AddError(
+			context,
+			KEY_ADDITIONAL_ITEMS,
+			false,
+			array,
+		)
+	}
+
+	if result.skips(strconv.Itoa(i), context) {
+		return
+	}
+	subContext := result.newContext(strconv.Itoa(i), context)
+
+	switch {
+
+	// items: false, as the false schema, fails on every item
+	case s.itemsBoolean != nil && !*s.itemsBoolean:
+		result.AddError(
+			subContext,
+			KEY_ITEMS,
+			false,
+			item,
+		)
+
+	// items is a schema, which every item must match
+	case s.itemsChildrenIsSingleSchema:
+		result.mergeErrors(s.itemsChildren[0].subValidateWithContext(item, array, subContext, result))
+
+	// items is an array of schemas, a tuple : each item must match the schema
+	// at its position, an array may be shorter than the tuple
+	case i < nbTupleItems:
+		result.mergeErrors(s.itemsChildren[i].subValidateWithContext(item, array, subContext, result))
+
+	// the items beyond the tuple are checked by additionalItems
+	case nbTupleItems > 0:
+		if additionalItems, ok := s.additionalItems.(*subSchema); ok {
+			result.mergeErrors(additionalItems.subValidateWithContext(item, array, subContext, result))
+		}
+	}
+}
+
 func (v *subSchema) validateArray(currentSubSchema *subSchema, value []interface{}, result *Result, context *JSONContext) {
 
 	internalLog("validateArray %s", context.String())
 	internalLog(" %v", value)
+	defer result.startTiming("validateArray")()
 
 	nbItems := len(value)
 
-	// TODO explain
-	if currentSubSchema.itemsChildrenIsSingleSchema {
-		for i := range value {
-			subContext := NewJSONContext(strconv.Itoa(i), context)
-			validationResult := currentSubSchema.itemsChildren[0].subValidateWithContext(value[i], subContext)
-			result.mergeErrors(validationResult)
-		}
-	} else {
-		if currentSubSchema.itemsChildren != nil && len(currentSubSchema.itemsChildren) > 0 {
-
-			nbItems := len(currentSubSchema.itemsChildren)
-			nbValues := len(value)
-
-			if nbItems == nbValues {
-				for i := 0; i != nbItems; i++ {
-					subContext := NewJSONContext(strconv.Itoa(i), context)
-					validationResult := currentSubSchema.itemsChildren[i].subValidateWithContext(value[i], subContext)
-					result.mergeErrors(validationResult)
-				}
-			} else if nbItems < nbValues {
-				switch currentSubSchema.additionalItems.(type) {
-				case bool:
-					if !currentSubSchema.additionalItems.(bool) {
-						result.AddError(
-							context,
-							KEY_ADDITIONAL_ITEMS,
-							currentSubSchema.additionalItems,
-							value,
-						)
-					}
-				case *subSchema:
-					additionalItemSchema := currentSubSchema.additionalItems.(*subSchema)
-					for i := nbItems; i != nbValues; i++ {
-						subContext := NewJSONContext(strconv.Itoa(i), context)
-						//TODO: see if this can be used in other rules that require validation and context modification
-						validationResult := additionalItemSchema.subValidateWithContext(value[i], subContext)
-						result.mergeErrors(validationResult)
-					}
-				}
-			}
-		}
+	for i := range value {
+		currentSubSchema.validateItem(value[i], i, value, result, context)
 	}
 
 	// minItems & maxItems
@@ -465,9 +1005,11 @@ func (v *subSchema) validateArray(currentSubSchema *subSchema, value []interface
 	}
 
 	// uniqueItems:
-	if currentSubSchema.uniqueItems != nil && *currentSubSchema.uniqueItems {
-		var stringifiedItems []string
-		for _, v := range value {
+	if currentSubSchema.uniqueItems != nil && *currentSubSchema.uniqueItems && !result.options.StructuralOnly {
+		stopTiming := result.startTiming(KEY_UNIQUE_ITEMS)
+		// index of the first item of each canonical JSON string
+		stringifiedItems := make(map[string]int, len(value))
+		for i, v := range value {
 			vString, err := marshalToJsonString(v)
 			if err != nil {
 				//TODO: better handling of errors like this? should this come back as a schema error?
@@ -477,25 +1019,64 @@ func (v *subSchema) validateArray(currentSubSchema *subSchema, value []interface
 					nil, // since the name is self explanatory and the requirement is subjective
 					value,
 				)
-			} else if isStringInSlice(stringifiedItems, *vString) {
-				result.AddError(
+				break
+			}
+			if first, ok := stringifiedItems[*vString]; ok {
+				// only the first duplicate is reported
+				result.addError(
 					context,
 					KEY_UNIQUE_ITEMS,
 					nil,
 					value,
+					map[string]interface{}{STRING_DUPLICATES: []int{first, i}},
+				)
+				break
+			}
+			stringifiedItems[*vString] = i
+		}
+		stopTiming()
+	}
+
+	// x-sorted & x-sortedBy:
+	if currentSubSchema.sorted != nil {
+		for i := 1; i < len(value); i++ {
+			previous, current := value[i-1], value[i]
+			if currentSubSchema.sortedBy != nil {
+				previous, current = sortKeyOf(previous, *currentSubSchema.sortedBy), sortKeyOf(current, *currentSubSchema.sortedBy)
+			}
+			order, ok := compareSortKeys(previous, current)
+			if !ok || (*currentSubSchema.sorted == SORTED_ASC && order > 0) || (*currentSubSchema.sorted == SORTED_DESC && order < 0) {
+				result.AddError(
+					result.newContext(strconv.Itoa(i), context),
+					KEY_X_SORTED,
+					*currentSubSchema.sorted,
+					value[i],
 				)
 			}
-			stringifiedItems = append(stringifiedItems, *vString)
 		}
 	}
 
 	result.incrementScore()
 }
 
-func (v *subSchema) validateObject(currentSubSchema *subSchema, value map[string]interface{}, result *Result, context *JSONContext) {
+// Returns the property of an item by which x-sortedBy sorts, nil when missing
+func sortKeyOf(item interface{}, property string) interface{} {
+	if m, ok := item.(map[string]interface{}); ok {
+		return m[property]
+	}
+	return nil
+}
+
+// Validates the object and the properties matched by patternProperties and
+// additionalProperties. Tells whether the object itself is valid : its number
+// of properties, its required properties and their names.
+func (v *subSchema) validateObject(currentSubSchema *subSchema, value map[string]interface{}, result *Result, context *JSONContext) bool {
 
 	internalLog("validateObject %s", context.String())
 	internalLog(" %v", value)
+	defer result.startTiming("validateObject")()
+
+	nbErrorsBefore := len(result.errors)
 
 	// minProperties & maxProperties:
 	if currentSubSchema.minProperties != nil {
@@ -520,120 +1101,126 @@ func (v *subSchema) validateObject(currentSubSchema *subSchema, value map[string
 	}
 
 	// required:
-	for _, requiredProperty := range currentSubSchema.required {
-		_, ok := value[requiredProperty]
+	required := currentSubSchema.required
+	if result.options.IgnoreRequired {
+		required = nil
+	}
+	for _, requiredProperty := range required {
+		propertyValue, ok := value[requiredProperty]
+		if !ok && result.options.CaseInsensitiveProperties {
+			propertyValue, ok = valueOfKeyFold(value, requiredProperty)
+		}
+		if ok && result.options.TreatEmptyStringAsAbsent && propertyValue == "" {
+			ok = false
+		}
 		if ok {
 			result.incrementScore()
 		} else {
 			result.AddError(
-				NewJSONContext(requiredProperty, context),
+				result.newContext(requiredProperty, context),
 				KEY_REQUIRED,
 				nil, // self explanatory and subjective
 				emptyProperty,
 			)
+			if result.options.UseTitleInErrors {
+				if title := currentSubSchema.propertyTitle(requiredProperty); title != nil {
+					result.errors[len(result.errors)-1].Title = *title
+				}
+			}
 		}
 	}
 
-	// additionalProperty & patternProperty:
-	if currentSubSchema.additionalProperties != nil {
-
-		switch currentSubSchema.additionalProperties.(type) {
-		case bool:
-
-			if !currentSubSchema.additionalProperties.(bool) {
-
-				for pk := range value {
-
-					found := false
-					for _, spValue := range currentSubSchema.propertiesChildren {
-						if pk == spValue.property {
-							found = true
-						}
-					}
-
-					pp_has, pp_match := v.validatePatternProperty(currentSubSchema, pk, value[pk], result, context)
-
-					if found {
-
-						if pp_has && !pp_match {
-							result.AddError(
-								NewJSONContext(pk, context),
-								KEY_ADDITIONAL_PROPERTIES,
-								currentSubSchema.patternProperties,
-								emptyProperty,
-							)
-						}
-
-					} else {
+	// propertyNames:
+	if currentSubSchema.propertyNames != nil {
+		for pk := range value {
+			validationResult := currentSubSchema.propertyNames.subValidateWithContext(pk, value, result.newContext(pk, context), result)
+			// tells the errors about the key from those about its value
+			validationResult.setErrorsDetail(KEY_PROPERTY_NAMES, pk)
+			result.mergeErrors(validationResult)
+		}
+	}
 
-						if !pp_has || !pp_match {
-							result.AddError(
-								NewJSONContext(pk, context),
-								KEY_ADDITIONAL_PROPERTIES,
-								nil, //TODO: we should show additionalProperties and patternProperties here...
-								emptyProperty,
-							)
-						}
-					}
-				}
+	// x-propertyOrder:
+	if keys, ok := result.options.keyOrders[reflect.ValueOf(value).Pointer()]; ok && currentSubSchema.propertyOrder != nil {
+		// the properties must follow the last one met
+		last := -1
+		for _, key := range keys {
+			position := indexOfString(currentSubSchema.propertyOrder, key)
+			if position < 0 {
+				continue
 			}
+			if position < last {
+				result.addError(
+					result.newContext(key, context),
+					KEY_X_PROPERTY_ORDER,
+					currentSubSchema.propertyOrder,
+					value[key],
+					map[string]interface{}{STRING_AFTER: currentSubSchema.propertyOrder[last]},
+				)
+			} else {
+				last = position
+			}
+		}
+	}
 
-		case *subSchema:
-
-			additionalPropertiesSchema := currentSubSchema.additionalProperties.(*subSchema)
-			for pk := range value {
-
-				found := false
-				for _, spValue := range currentSubSchema.propertiesChildren {
-					if pk == spValue.property {
-						found = true
-					}
-				}
-
-				pp_has, pp_match := v.validatePatternProperty(currentSubSchema, pk, value[pk], result, context)
-
-				if found {
-
-					//TODO double check
-					if pp_has && !pp_match {
-						validationResult := additionalPropertiesSchema.subValidateWithContext(value[pk], context)
-						result.mergeErrors(validationResult)
-					}
-
-				} else {
-
-					if !pp_has || !pp_match {
-						validationResult := additionalPropertiesSchema.subValidateWithContext(value[pk], context)
-						result.mergeErrors(validationResult)
-					}
+	objectValid := len(result.errors) == nbErrorsBefore
+	if !objectValid && result.options.PropertyDescent == DESCENT_ON_VALID_OBJECT {
+		result.incrementScore()
+		return false
+	}
 
-				}
+	// patternProperty & additionalProperty:
+	for pk := range value {
 
-			}
+		if result.skips(pk, context) {
+			continue
 		}
-	} else {
 
-		for pk := range value {
-
-			pp_has, pp_match := v.validatePatternProperty(currentSubSchema, pk, value[pk], result, context)
+		// every matching patternProperties subSchema applies, whatever additionalProperties is
+		pp_has, _ := v.validatePatternProperty(currentSubSchema, pk, value[pk], value, result, context)
 
-			if pp_has && !pp_match {
+		if pp_has || currentSubSchema.hasPropertyChild(pk) {
+			continue
+		}
+		if result.options.CaseInsensitiveProperties && currentSubSchema.propertyChildFold(pk) != nil {
+			continue
+		}
 
+		// pk is an additional property
+		switch additionalProperties := currentSubSchema.additionalProperties.(type) {
+		case bool:
+			if additionalProperties {
+				result.evaluateProperty(context, pk)
+			} else {
 				result.AddError(
-					NewJSONContext(pk, context),
-					KEY_PATTERN_PROPERTIES,
-					currentSubSchema.patternProperties,
-					value,
+					result.newContext(pk, context),
+					KEY_ADDITIONAL_PROPERTIES,
+					currentSubSchema.allowedPropertiesRequirement(result.options.AdditionalPropertyErrorDetail),
+					value[pk],
 				)
 			}
 
+		case *subSchema:
+			result.evaluateProperty(context, pk)
+			validationResult := additionalProperties.subValidateWithContext(value[pk], value, result.newContext(pk, context), result)
+			result.mergeErrors(validationResult)
+
+		case nil:
+			if result.options.FallbackAdditionalSchema != nil {
+				result.evaluateProperty(context, pk)
+				fallbackSchema := result.options.FallbackAdditionalSchema.rootSchema
+				validationResult := fallbackSchema.subValidateWithContext(value[pk], value, result.newContext(pk, context), result)
+				result.mergeErrors(validationResult)
+			}
 		}
 	}
 
 	result.incrementScore()
+
+	return objectValid
 }
 
-func (v *subSchema) validatePatternProperty(currentSubSchema *subSchema, key string, value interface{}, result *Result, context *JSONContext) (has bool, matched bool) {
+func (v *subSchema) validatePatternProperty(currentSubSchema *subSchema, key string, value interface{}, parentNode interface{}, result *Result, context *JSONContext) (has bool, matched bool) {
 
 	internalLog("validatePatternProperty %s", context.String())
 	internalLog(" %s %v", key, value)
@@ -645,8 +1232,9 @@ func (v *subSchema) validatePatternProperty(currentSubSchema *subSchema, key str
 	for pk, pv := range currentSubSchema.patternProperties {
 		if matches, _ := regexp.MatchString(pk, key); matches {
 			has = true
-			subContext := NewJSONContext(key, context)
-			validationResult := pv.subValidateWithContext(value, subContext)
+			result.evaluateProperty(context, key)
+			subContext := result.newContext(key, context)
+			validationResult := pv.subValidateWithContext(value, parentNode, subContext, result)
 			result.mergeErrors(validationResult)
 			if validationResult.Valid() {
 				validatedkey = true
@@ -667,6 +1255,7 @@ func (v *subSchema) validateString(currentSubSchema *subSchema, value interface{
 
 	internalLog("validateString %s", context.String())
 	internalLog(" %v", value)
+	defer result.startTiming("validateString")()
 
 	// Ignore non strings
 	if !isKind(value, reflect.String) {
@@ -675,9 +1264,22 @@ func (v *subSchema) validateString(currentSubSchema *subSchema, value interface{
 
 	stringValue := value.(string)
 
+	switch result.options.NormalizeUnicode {
+	case NORMALIZE_NFC:
+		stringValue = norm.NFC.String(stringValue)
+	case NORMALIZE_NFD:
+		stringValue = norm.NFD.String(stringValue)
+	}
+
 	// minLength & maxLength:
+	var stringLength int
+	if result.options.LengthUnit == LENGTH_IN_BYTES {
+		stringLength = len(stringValue)
+	} else {
+		stringLength = utf8.RuneCountInString(stringValue)
+	}
 	if currentSubSchema.minLength != nil {
-		if utf8.RuneCount([]byte(stringValue)) < *currentSubSchema.minLength {
+		if stringLength < *currentSubSchema.minLength {
 			result.AddError(
 				context,
 				KEY_MIN_LENGTH,
@@ -687,7 +1289,7 @@ func (v *subSchema) validateString(currentSubSchema *subSchema, value interface{
 		}
 	}
 	if currentSubSchema.maxLength != nil {
-		if utf8.RuneCount([]byte(stringValue)) > *currentSubSchema.maxLength {
+		if stringLength > *currentSubSchema.maxLength {
 			result.AddError(
 				context,
 				KEY_MAX_LENGTH,
@@ -698,17 +1300,93 @@ func (v *subSchema) validateString(currentSubSchema *subSchema, value interface{
 	}
 
 	// pattern:
-	if currentSubSchema.pattern != nil {
+	if currentSubSchema.pattern != nil && !result.options.StructuralOnly {
+		stopTiming := result.startTiming(KEY_PATTERN)
 		if !currentSubSchema.pattern.MatchString(stringValue) {
-			result.AddError(
+			pattern := currentSubSchema.pattern.String()
+			result.addError(
 				context,
 				KEY_PATTERN,
-				currentSubSchema.pattern.String(),
+				pattern,
+				value,
+				map[string]interface{}{KEY_PATTERN: pattern, STRING_VALUE: value},
+			)
+		}
+		stopTiming()
+	}
+
+	// format:
+	stopTiming := result.startTiming(KEY_FORMAT)
+	// an empty string stands for an absent property, see TreatEmptyStringAsAbsent
+	absent := result.options.TreatEmptyStringAsAbsent && stringValue == ""
+	isFormat := currentSubSchema.format == nil || result.options.StructuralOnly || absent || FormatCheckers.IsFormat(*currentSubSchema.format, stringValue)
+	stopTiming()
+	if !isFormat {
+		if result.options.StrictFormat {
+			result.AddError(
+				context,
+				KEY_FORMAT,
+				*currentSubSchema.format,
+				value,
+			)
+		} else {
+			result.addAnnotation(
+				context,
+				KEY_FORMAT,
+				*currentSubSchema.format,
 				value,
 			)
 		}
 	}
 
+	// x-anyFormat:
+	if currentSubSchema.anyFormat != nil && !result.options.StructuralOnly && !absent {
+		matched := false
+		for _, format := range currentSubSchema.anyFormat {
+			if FormatCheckers.Has(format) && FormatCheckers.IsFormat(format, stringValue) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			result.AddError(
+				context,
+				KEY_X_ANY_FORMAT,
+				currentSubSchema.anyFormat,
+				value,
+			)
+		}
+	}
+
+	// contentEncoding & contentMediaType:
+	if result.options.ValidateContent {
+		content := []byte(stringValue)
+		contentDecoded := true
+		if currentSubSchema.contentEncoding != nil && *currentSubSchema.contentEncoding == CONTENT_ENCODING_BASE64 {
+			decoded, err := base64.StdEncoding.DecodeString(stringValue)
+			if err != nil {
+				result.AddError(
+					context,
+					KEY_CONTENT_ENCODING,
+					*currentSubSchema.contentEncoding,
+					value,
+				)
+				contentDecoded = false
+			}
+			content = decoded
+		}
+		if contentDecoded && currentSubSchema.contentMediaType != nil && *currentSubSchema.contentMediaType == CONTENT_MEDIA_TYPE_JSON {
+			if !json.Valid(content) {
+				result.AddError(
+					context,
+					KEY_CONTENT_MEDIA_TYPE,
+					*currentSubSchema.contentMediaType,
+					value,
+				)
+			}
+		}
+	}
+
 	result.incrementScore()
 }
 
@@ -716,6 +1394,7 @@ func (v *subSchema) validateNumber(currentSubSchema *subSchema, value interface{
 
 	internalLog("validateNumber %s", context.String())
 	internalLog(" %v", value)
+	defer result.startTiming("validateNumber")()
 
 	// Ignore non numbers
 	if !isKind(value, reflect.Float64) {
@@ -726,7 +1405,7 @@ func (v *subSchema) validateNumber(currentSubSchema *subSchema, value interface{
 
 	// multipleOf:
 	if currentSubSchema.multipleOf != nil {
-		if !isFloat64AnInteger(float64Value / *currentSubSchema.multipleOf) {
+		if !isMultipleOf(float64Value, *currentSubSchema.multipleOf) {
 			result.AddError(
 				context,
 				KEY_MULTIPLE_OF,
@@ -782,5 +1461,28 @@ func (v *subSchema) validateNumber(currentSubSchema *subSchema, value interface{
 		}
 	}
 
+	// x-format:
+	if currentSubSchema.numberFormat != nil {
+		bounds := numberFormatRanges[*currentSubSchema.numberFormat]
+		if float64Value < bounds[0] || float64Value > bounds[1] {
+			result.AddError(
+				context,
+				KEY_X_FORMAT,
+				*currentSubSchema.numberFormat,
+				resultErrorFormatNumber(float64Value),
+			)
+		}
+	}
+
+	// x-maxDecimals:
+	if currentSubSchema.maxDecimals != nil && result.decimalPlaces(float64Value, context) > *currentSubSchema.maxDecimals {
+		result.AddError(
+			context,
+			KEY_X_MAX_DECIMALS,
+			currentSubSchema.maxDecimals,
+			resultErrorFormatNumber(float64Value),
+		)
+	}
+
 	result.incrementScore()
 }
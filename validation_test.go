@@ -0,0 +1,2291 @@
+// Copyright 2015 xeipuuv ( https://github.com/xeipuuv )
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// author           xeipuuv
+// author-github    https://github.com/xeipuuv
+// author-mail      xeipuuv@gmail.com
+//
+// repository-name  gojsonschema
+// repository-desc  An implementation of JSON Schema, based on IETF's draft v4 - Go language.
+//
+// description      (Unit) Tests for the validation phase and its options.
+//
+// created          16-10-2026
+
+package gojsonschema
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFallbackAdditionalSchema(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {"id": {"type": "integer"}},
+		"patternProperties": {"^x-": {}}
+	}`))
+	assert.Nil(t, err)
+
+	fallback, err := NewSchema(NewStringLoader(`{"type": "string"}`))
+	assert.Nil(t, err)
+
+	document := NewStringLoader(`{"id": 1, "x-count": 2, "name": "a", "size": 3}`)
+
+	result, err := schema.Validate(document)
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	result, err = schema.ValidateWithOptions(document, ValidateOptions{FallbackAdditionalSchema: fallback})
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, "#/size", result.Errors()[0].Context.String())
+		assert.Equal(t, KEY_TYPE, result.Errors()[0].Reason)
+	}
+
+	// an explicit additionalProperties takes precedence over the fallback
+	schema, err = NewSchema(NewStringLoader(`{"additionalProperties": true}`))
+	assert.Nil(t, err)
+	result, err = schema.ValidateWithOptions(document, ValidateOptions{FallbackAdditionalSchema: fallback})
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+}
+
+func TestCaptureDocument(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"required": ["password"]}`))
+	assert.Nil(t, err)
+
+	document := NewStringLoader(`{"user": "a", "password": "secret"}`)
+
+	result, err := schema.Validate(document)
+	assert.Nil(t, err)
+	assert.Nil(t, result.Document())
+
+	result, err = schema.ValidateWithOptions(document, ValidateOptions{CaptureDocument: true})
+	assert.Nil(t, err)
+	assert.Equal(t, map[string]interface{}{"user": "a", "password": "secret"}, result.Document())
+
+	redact := func(document interface{}) interface{} {
+		document.(map[string]interface{})["password"] = "***"
+		return document
+	}
+	result, err = schema.ValidateWithOptions(document, ValidateOptions{CaptureDocument: true, RedactDocument: redact})
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+	assert.Equal(t, map[string]interface{}{"user": "a", "password": "***"}, result.Document())
+}
+
+func TestSchemaDependencyErrors(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"dependencies": {
+			"credit_card": {"required": ["billing_address"]}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	result, err := schema.Validate(NewStringLoader(`{"name": "a"}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	result, err = schema.Validate(NewStringLoader(`{"name": "a", "credit_card": 5555555555554444}`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		resultError := result.Errors()[0]
+		assert.Equal(t, KEY_REQUIRED, resultError.Reason)
+		assert.Equal(t, "#/credit_card/billing_address", resultError.Context.String())
+		assert.Equal(t, "credit_card", resultError.Details[STRING_DEPENDENCY])
+	}
+
+	result, err = schema.Validate(NewStringLoader(`{"credit_card": 5555555555554444, "billing_address": "street"}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+}
+
+func TestTreatEmptyStringAsAbsent(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"required": ["name", "age"]}`))
+	assert.Nil(t, err)
+
+	document := NewStringLoader(`{"name": "", "age": 0}`)
+
+	result, err := schema.Validate(document)
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	result, err = schema.ValidateWithOptions(document, ValidateOptions{TreatEmptyStringAsAbsent: true})
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, KEY_REQUIRED, result.Errors()[0].Reason)
+		assert.Equal(t, "#/name", result.Errors()[0].Context.String())
+	}
+
+	// an empty optional email is not a format error, an empty string is still
+	// checked by the other keywords
+	schema, err = NewSchemaWithOptions(NewStringLoader(`{"properties": {
+		"email": {"type": "string", "format": "email"},
+		"contact": {"type": "string", "x-anyFormat": ["email", "ipv4"]},
+		"code": {"type": "string", "minLength": 1, "format": "email"}
+	}}`), SchemaLoaderOptions{EnableExtensions: true})
+	assert.Nil(t, err)
+	document = NewStringLoader(`{"email": "", "contact": "", "code": ""}`)
+
+	result, err = schema.ValidateWithOptions(document, ValidateOptions{TreatEmptyStringAsAbsent: true, StrictFormat: true})
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, KEY_MIN_LENGTH, result.Errors()[0].Reason)
+	}
+
+	result, err = schema.ValidateWithOptions(document, ValidateOptions{StrictFormat: true})
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 4)
+}
+
+type recordingObserver struct {
+	entered []string
+	errors  []string
+}
+
+func (o *recordingObserver) OnEnter(context *JSONContext, schemaLocation string) {
+	o.entered = append(o.entered, context.String()+" "+schemaLocation)
+}
+
+func (o *recordingObserver) OnError(resultError ResultError) {
+	o.errors = append(o.errors, resultError.String())
+}
+
+func TestObserver(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {"a~b": {"$ref": "#/definitions/positive"}},
+		"items": [{"type": "string"}],
+		"definitions": {"positive": {"minimum": 0}}
+	}`))
+	assert.Nil(t, err)
+
+	observer := &recordingObserver{}
+	result, err := schema.ValidateWithOptions(NewStringLoader(`{"a~b": -1}`), ValidateOptions{Observer: observer})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"# #", "#/a~b #/properties/a~0b", "#/a~b #/definitions/positive"}, observer.entered)
+	assert.Equal(t, []string{result.Errors()[0].String()}, observer.errors)
+
+	observer = &recordingObserver{}
+	_, err = schema.ValidateWithOptions(NewStringLoader(`[1]`), ValidateOptions{Observer: observer})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"# #", "#/0 #/items/0"}, observer.entered)
+}
+
+func TestPatternFlagsExtension(t *testing.T) {
+
+	schemaLoader := NewStringLoader(`{"pattern": "^abc$", "x-patternFlags": "i"}`)
+	document := NewStringLoader(`"ABC"`)
+
+	schema, err := NewSchema(schemaLoader)
+	assert.Nil(t, err)
+	result, err := schema.Validate(document)
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+
+	schema, err = NewSchemaWithOptions(schemaLoader, SchemaLoaderOptions{EnableExtensions: true})
+	assert.Nil(t, err)
+	result, err = schema.Validate(document)
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	_, err = NewSchemaWithOptions(NewStringLoader(`{"pattern": "a", "x-patternFlags": "g"}`), SchemaLoaderOptions{EnableExtensions: true})
+	assert.NotNil(t, err)
+}
+
+func TestAnchorPatterns(t *testing.T) {
+
+	schemaLoader := NewStringLoader(`{"pattern": "b|c", "x-patternFlags": "i"}`)
+
+	schema, err := NewSchemaWithOptions(schemaLoader, SchemaLoaderOptions{EnableExtensions: true})
+	assert.Nil(t, err)
+	result, err := schema.Validate(NewStringLoader(`"abc"`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	schema, err = NewSchemaWithOptions(schemaLoader, SchemaLoaderOptions{EnableExtensions: true, AnchorPatterns: true})
+	assert.Nil(t, err)
+	result, err = schema.Validate(NewStringLoader(`"abc"`))
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+	assert.Equal(t, `(?i)\A(?:b|c)\z`, result.Errors()[0].Details["pattern"])
+	result, err = schema.Validate(NewStringLoader(`"C"`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	// the whole string, not a line of it
+	schema, err = NewSchemaWithOptions(NewStringLoader(`{"pattern": "ok", "x-patternFlags": "m"}`), SchemaLoaderOptions{EnableExtensions: true, AnchorPatterns: true})
+	assert.Nil(t, err)
+	for document, valid := range map[string]bool{`"ok"`: true, `"ok\nanything"`: false, `"anything\nok"`: false} {
+		result, err = schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+}
+
+func TestStructuralOnly(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"type": "object",
+		"required": ["id"],
+		"properties": {
+			"id": {"type": "string", "pattern": "^[0-9]+$", "format": "email"},
+			"tags": {"type": "array", "uniqueItems": true},
+			"kind": {"anyOf": [{"const": "a"}, {"const": "b"}], "not": {"type": "string"}}
+		},
+		"allOf": [{"maxProperties": 1}]
+	}`))
+	assert.Nil(t, err)
+	document := NewStringLoader(`{"id": "x", "tags": [1, 1], "kind": "c"}`)
+
+	result, err := schema.ValidateWithOptions(document, ValidateOptions{StrictFormat: true})
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 6)
+
+	result, err = schema.ValidateWithOptions(document, ValidateOptions{StrictFormat: true, StructuralOnly: true})
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+	assert.Empty(t, result.Annotations())
+
+	result, err = schema.ValidateWithOptions(NewStringLoader(`{"id": 1, "tags": {}}`), ValidateOptions{StructuralOnly: true})
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 2)
+}
+
+func TestPropertiesCount(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"minProperties": 3,
+		"maxProperties": 4,
+		"properties": {"id": {}},
+		"patternProperties": {"^x-": {}, "^x-a": {}, "^y-": {"type": "integer"}},
+		"additionalProperties": {"type": "string"}
+	}`))
+	assert.Nil(t, err)
+	reasons := func(result *Result) []string {
+		var reasons []string
+		for _, rerr := range result.Errors() {
+			reasons = append(reasons, rerr.Reason)
+		}
+		sort.Strings(reasons)
+		return reasons
+	}
+
+	// every key counts once, whether it is declared, matched by one or several
+	// patternProperties or additional, and whatever its value, null included
+	for document, valid := range map[string]bool{
+		`{"id": 1, "x-a": 1}`:                                  false,
+		`{"id": 1, "x-a": 1, "other": "a"}`:                    true,
+		`{"x-a": 1, "x-ab": 1, "x-b": 1}`:                      true,
+		`{"id": null, "x-a": null, "y-": 1, "other": "a"}`:     true,
+		`{"id": 1, "x-a": 1, "x-b": 1, "y-": 1, "other": "a"}`: false,
+		`{"x-1": 1, "x-2": 1, "x-3": 1, "x-4": 1, "x-5": 1}`:   false,
+	} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	// the properties failing their subSchema still count
+	result, err := schema.Validate(NewStringLoader(`{"y-": "a", "other": 1}`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{KEY_MIN_PROPERTIES, KEY_TYPE, KEY_TYPE}, reasons(result))
+
+	// a duplicated key is decoded as a single property, its last value
+	result, err = schema.Validate(NewStringLoader(`{"id": 1, "id": 2, "id": 3}`))
+	assert.Nil(t, err)
+	assert.Equal(t, []string{KEY_MIN_PROPERTIES}, reasons(result))
+	result, err = schema.Validate(NewStringLoader(`{"id": 1, "x-a": 1, "x-b": 1, "other": "a", "other": "b"}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	// keys differing by case are distinct, even when they match the same property
+	result, err = schema.ValidateWithOptions(NewStringLoader(`{"id": 1, "ID": 1, "Id": 1}`), ValidateOptions{CaseInsensitiveProperties: true})
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+}
+
+func TestLengthUnit(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"maxLength": 4}`))
+	assert.Nil(t, err)
+
+	document := NewStringLoader(`"café"`)
+
+	result, err := schema.Validate(document)
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	result, err = schema.ValidateWithOptions(document, ValidateOptions{LengthUnit: LENGTH_IN_BYTES})
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, KEY_MAX_LENGTH, result.Errors()[0].Reason)
+	}
+}
+
+func TestPatternError(t *testing.T) {
+
+	result, err := Validate(NewStringLoader(`{"pattern": "^[0-9]+$"}`), NewStringLoader(`"12a"`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		resultError := result.Errors()[0]
+		assert.Equal(t, "^[0-9]+$", resultError.Requirement)
+		assert.Equal(t, map[string]interface{}{KEY_PATTERN: "^[0-9]+$", STRING_VALUE: "12a"}, resultError.Details)
+		assert.Equal(t, `{"#":[["pattern","^[0-9]+$"]]}`, mustMarshal(t, result.Errors()))
+	}
+}
+
+func mustMarshal(t *testing.T, v interface{}) string {
+	b, err := json.Marshal(v)
+	assert.Nil(t, err)
+	return string(b)
+}
+
+func TestDependentSchemas(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"dependentSchemas": {"credit_card": {"required": ["billing_address"]}},
+		"dependencies": {"coupon": ["total"]}
+	}`))
+	assert.Nil(t, err)
+
+	result, err := schema.Validate(NewStringLoader(`{"credit_card": 1, "coupon": "A"}`))
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 2)
+	for _, resultError := range result.Errors() {
+		switch resultError.Reason {
+		case KEY_REQUIRED:
+			assert.Equal(t, "#/credit_card/billing_address", resultError.Context.String())
+			assert.Equal(t, "credit_card", resultError.Details[STRING_DEPENDENCY])
+		case KEY_DEPENDENCIES:
+			assert.Equal(t, "#/coupon", resultError.Context.String())
+		default:
+			t.Errorf("unexpected error %s", resultError)
+		}
+	}
+
+	result, err = schema.Validate(NewStringLoader(`{"credit_card": 1, "billing_address": "street"}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+}
+
+func TestIsValid(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {"a": {"items": {"type": "integer"}}},
+		"required": ["b"]
+	}`))
+	assert.Nil(t, err)
+
+	document := NewStringLoader(`{"a": [1, "2"]}`)
+
+	result, err := schema.Validate(document)
+	assert.Nil(t, err)
+	expected := result.Errors()
+
+	result, err = schema.ValidateWithOptions(document, ValidateOptions{IsValid: true})
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+	if assert.Len(t, result.Errors(), len(expected)) {
+		for i, resultError := range result.Errors() {
+			assert.Nil(t, resultError.Context)
+			assert.Equal(t, expected[i].Reason, resultError.Reason)
+		}
+	}
+
+	result, err = schema.ValidateWithOptions(NewStringLoader(`{"a": [1], "b": 2}`), ValidateOptions{IsValid: true})
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+}
+
+// A schema describing itself recursively and a valid document nested 50 levels deep
+func deepDocumentBenchmark(b *testing.B) (*Schema, JSONLoader) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"type": "object",
+		"properties": {
+			"name": {"type": "string", "minLength": 1},
+			"children": {"type": "array", "items": {"$ref": "#"}}
+		},
+		"required": ["name"]
+	}`))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	document := map[string]interface{}{"name": "leaf"}
+	for i := 0; i < 50; i++ {
+		document = map[string]interface{}{
+			"name":     "node",
+			"children": []interface{}{document, map[string]interface{}{"name": "sibling"}},
+		}
+	}
+
+	return schema, NewGoLoader(document)
+}
+
+func BenchmarkValidateDeepDocument(b *testing.B) {
+
+	schema, document := deepDocumentBenchmark(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		schema.Validate(document)
+	}
+}
+
+func BenchmarkIsValidDeepDocument(b *testing.B) {
+
+	schema, document := deepDocumentBenchmark(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		schema.ValidateWithOptions(document, ValidateOptions{IsValid: true})
+	}
+}
+
+func TestUniqueItems(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"uniqueItems": true}`))
+	assert.Nil(t, err)
+
+	for document, valid := range map[string]bool{
+		`[1, 2, "1", [1], {"a": 1}]`:           true,
+		`[1, 1.0]`:                             false,
+		`[{"a": 1, "b": 2}, {"b": 2, "a": 1}]`: false,
+		`[[1, 2], [2, 1]]`:                     true,
+		`[null, false, 0, "", [], {}]`:         true,
+		`["a", "b", "a", "b", "b"]`:            false,
+	} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	// only the first duplicate is reported
+	result, err := schema.Validate(NewStringLoader(`["a", "b", "c", "b", "a"]`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, []int{1, 3}, result.Errors()[0].Details[STRING_DUPLICATES])
+	}
+}
+
+func BenchmarkUniqueItems(b *testing.B) {
+
+	schema, err := NewSchema(NewStringLoader(`{"uniqueItems": true}`))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	items := make([]string, 100000)
+	for i := range items {
+		items[i] = strconv.Itoa(i)
+	}
+	document := NewStringLoader("[" + strings.Join(items, ",") + "]")
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		schema.Validate(document)
+	}
+}
+
+// A wide schema without anyOf nor oneOf, for which scoring the results is
+// of no use, validated with and without the scores
+func BenchmarkValidateWideSchema(b *testing.B) {
+
+	properties := make([]string, 500)
+	values := make([]string, 500)
+	for i := range properties {
+		properties[i] = fmt.Sprintf(`"p%d": {"type": "object", "properties": {"n": {"type": "integer", "minimum": 0}, "s": {"type": "string"}}, "required": ["n"]}`, i)
+		values[i] = fmt.Sprintf(`"p%d": {"n": %d, "s": "v"}`, i, i)
+	}
+	schema, err := NewSchema(NewStringLoader("{\"properties\": {" + strings.Join(properties, ",") + "}}"))
+	if err != nil {
+		b.Fatal(err)
+	}
+	// decoded once, only the validation is measured
+	document, err := decodeJSONUseNumber([]byte("{" + strings.Join(values, ",") + "}"))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.Run("scored", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			schema.validateDocument(document, ValidateOptions{})
+		}
+	})
+	b.Run("unscored", func(b *testing.B) {
+		b.ReportAllocs()
+		for i := 0; i < b.N; i++ {
+			schema.validateDocument(document, ValidateOptions{unscored: true})
+		}
+	})
+}
+
+func TestUnscoredResults(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"properties": {
+		"a": {"type": "integer", "minimum": 0},
+		"b": {"type": "object", "properties": {"c": {"type": "string"}}, "required": ["c"]}
+	}}`))
+	assert.Nil(t, err)
+	assert.False(t, schema.combined)
+
+	for _, document := range []string{`{"a": 1, "b": {"c": "x"}}`, `{"a": -1, "b": {"c": 2}}`, `{"b": {}}`} {
+		var options ValidateOptions
+		root, err := loadDocument(NewStringLoader(document), &options, schema)
+		assert.Nil(t, err)
+		assert.True(t, options.unscored)
+		scored := schema.validateDocument(root, ValidateOptions{})
+		unscored := schema.validateDocument(root, options)
+		assert.Equal(t, scored.Errors(), unscored.Errors(), document)
+		assert.Equal(t, 0, unscored.score, document)
+	}
+
+	for _, source := range []string{`{"anyOf": [{"type": "string"}]}`, `{"properties": {"a": {"oneOf": [{"type": "string"}]}}}`} {
+		schema, err := NewSchema(NewStringLoader(source))
+		assert.Nil(t, err)
+		assert.True(t, schema.combined, source)
+	}
+
+	// the scores pick the subSchema of the anyOf matching the most properties,
+	// be it in the fallback schema or in an override
+	anyOf, err := NewSchema(NewStringLoader(`{"anyOf": [
+		{"properties": {"a": {"type": "string"}, "b": {"type": "string"}}},
+		{"properties": {"c": {"type": "string"}}}
+	]}`))
+	assert.Nil(t, err)
+	document := NewStringLoader(`{"x": {"a": "x", "b": 1, "c": 1}}`)
+
+	result, err := schema.ValidateWithOptions(document, ValidateOptions{FallbackAdditionalSchema: anyOf})
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, "#/x/b", result.Errors()[0].Context.String())
+	}
+
+	schema, err = NewSchema(NewStringLoader(`{"properties": {"x": {"$ref": "#/definitions/x"}}, "definitions": {"x": {}}}`))
+	assert.Nil(t, err)
+	result, err = schema.ValidateWithOverrides(document, map[string]*Schema{"#/definitions/x": anyOf})
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, "#/x/b", result.Errors()[0].Context.String())
+	}
+}
+
+func TestOneOf(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"oneOf": [
+		{"type": "integer"},
+		{"type": "number", "minimum": 2},
+		{"type": "string"},
+		{"type": "number", "maximum": 10}
+	]}`))
+	assert.Nil(t, err)
+
+	for _, test := range []struct {
+		document string
+		valid    bool
+	}{
+		{`"abc"`, true},
+		{`20.5`, true},
+		{`1`, false},
+		{`5`, false},
+		{`null`, false},
+	} {
+		result, err := schema.Validate(NewStringLoader(test.document))
+		assert.Nil(t, err)
+		assert.Equal(t, test.valid, result.Valid(), test.document)
+	}
+
+	// whether the other subSchemas match or not
+	for _, document := range []string{`1`, `5`} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		if assert.Len(t, result.Errors(), 1, document) {
+			assert.Equal(t, KEY_ONE_OF, result.Errors()[0].Reason, document)
+		}
+	}
+}
+
+// A wide oneOf, which fails as soon as a second subSchema matches
+func BenchmarkValidateWideOneOf(b *testing.B) {
+
+	branches := make([]string, 200)
+	for i := range branches {
+		branches[i] = fmt.Sprintf(`{"properties": {"kind": {"enum": ["k%d", "any"]}, "n": {"type": "integer"}}, "required": ["kind", "n"]}`, i)
+	}
+	schema, err := NewSchema(NewStringLoader("{\"items\": {\"oneOf\": [" + strings.Join(branches, ",") + "]}}"))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, benchmark := range []struct {
+		name string
+		item func(i int) string
+	}{
+		{"single", func(i int) string { return fmt.Sprintf(`{"kind": "k%d", "n": 1}`, i%len(branches)) }},
+		{"several", func(i int) string { return `{"kind": "any", "n": 1}` }},
+		{"none", func(i int) string { return `{"kind": "none", "n": 1}` }},
+	} {
+		items := make([]string, 100)
+		for i := range items {
+			items[i] = benchmark.item(i)
+		}
+		// decoded once, only the validation is measured
+		document, err := decodeJSONUseNumber([]byte("[" + strings.Join(items, ",") + "]"))
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		b.Run(benchmark.name, func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				schema.validateDocument(document, ValidateOptions{})
+			}
+		})
+	}
+}
+
+func TestValidateURL(t *testing.T) {
+
+	dir, err := ioutil.TempDir("", "gojsonschema")
+	assert.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	schemaPath := filepath.Join(dir, "schema.json")
+	err = ioutil.WriteFile(schemaPath, []byte(`{"type": "string"}`), 0644)
+	assert.Nil(t, err)
+
+	result, err := ValidateURL("file://"+schemaPath, NewStringLoader(`42`))
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+
+	// the schema cannot be loaded
+	_, err = ValidateURL("file://"+filepath.Join(dir, "missing.json"), NewStringLoader(`42`))
+	if assert.IsType(t, &SchemaLoadError{}, err) {
+		assert.Equal(t, "file://"+filepath.Join(dir, "missing.json"), err.(*SchemaLoadError).URL)
+	}
+
+	// the document cannot be loaded
+	_, err = ValidateURL("file://"+schemaPath, NewStringLoader(`{`))
+	assert.NotNil(t, err)
+	_, isSchemaLoadError := err.(*SchemaLoadError)
+	assert.False(t, isSchemaLoadError)
+}
+
+func TestSortedExtension(t *testing.T) {
+
+	extensions := SchemaLoaderOptions{EnableExtensions: true}
+
+	schema, err := NewSchema(NewStringLoader(`{"x-sorted": "asc"}`))
+	assert.Nil(t, err)
+	result, err := schema.Validate(NewStringLoader(`[3, 1]`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	schema, err = NewSchemaWithOptions(NewStringLoader(`{"x-sorted": "asc"}`), extensions)
+	assert.Nil(t, err)
+	for document, valid := range map[string]bool{`[]`: true, `[1, 1, 2.5]`: true, `["a", "b"]`: true, `[1, 3, 2]`: false, `[1, "a"]`: false} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	schema, err = NewSchemaWithOptions(NewStringLoader(`{"x-sorted": "desc", "x-sortedBy": "date"}`), extensions)
+	assert.Nil(t, err)
+	result, err = schema.Validate(NewStringLoader(`[{"date": "2020-02-01"}, {"date": "2020-03-01"}, {"date": "2020-01-01"}, {}]`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 2) {
+		assert.Equal(t, "#/1", result.Errors()[0].Context.String())
+		assert.Equal(t, KEY_X_SORTED, result.Errors()[0].Reason)
+		assert.Equal(t, "#/3", result.Errors()[1].Context.String())
+	}
+
+	_, err = NewSchemaWithOptions(NewStringLoader(`{"x-sorted": "up"}`), extensions)
+	assert.NotNil(t, err)
+	_, err = NewSchemaWithOptions(NewStringLoader(`{"x-sortedBy": "date"}`), extensions)
+	assert.NotNil(t, err)
+}
+
+func TestPropertyOrderExtension(t *testing.T) {
+
+	extensions := SchemaLoaderOptions{EnableExtensions: true}
+
+	schema, err := NewSchema(NewStringLoader(`{"x-propertyOrder": ["a", "b"]}`))
+	assert.Nil(t, err)
+	result, err := schema.Validate(NewStringLoader(`{"b": 1, "a": 2}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	schema, err = NewSchemaWithOptions(NewStringLoader(`{
+		"x-propertyOrder": ["id", "name", "tags"],
+		"properties": {"tags": {"items": {"x-propertyOrder": ["k", "v"]}}}
+	}`), extensions)
+	assert.Nil(t, err)
+	for document, valid := range map[string]bool{
+		`{}`:                                 true,
+		`{"id": 1, "name": "a", "tags": []}`: true,
+		`{"id": 1, "other": 0, "tags": [], "more": 0}`:   true,
+		`{"name": "a", "id": 1}`:                         false,
+		`{"id": 1, "tags": [{"v": 1, "k": 2}]}`:          false,
+		`{"id": 1, "tags": [{"k": 1, "v": 2}], "id": 2}`: true,
+	} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	result, err = schema.Validate(NewStringLoader(`{"tags": [], "name": "a", "id": 1}`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 2) {
+		assert.Equal(t, "#/name", result.Errors()[0].Context.String())
+		assert.Equal(t, KEY_X_PROPERTY_ORDER, result.Errors()[0].Reason)
+		assert.Equal(t, "tags", result.Errors()[0].Details[STRING_AFTER])
+		assert.Equal(t, "#/id", result.Errors()[1].Context.String())
+	}
+
+	// the order is also checked with IsValid, and kept by the compiled schemas
+	result, err = schema.ValidateWithOptions(NewStringLoader(`{"name": "a", "id": 1}`), ValidateOptions{IsValid: true})
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+	data, err := schema.MarshalCompiled()
+	assert.Nil(t, err)
+	compiled, err := LoadCompiled(data)
+	assert.Nil(t, err)
+	result, err = compiled.Validate(NewStringLoader(`{"name": "a", "id": 1}`))
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+
+	// the Go values have no order
+	result, err = schema.Validate(NewGoLoader(map[string]interface{}{"name": "a", "id": 1}))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	// the order is checked by every way to validate a document
+	var out map[string]interface{}
+	result, err = schema.ValidateInto(NewStringLoader(`{"name": "a", "id": 1}`), &out)
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+	_, result, err = schema.ValidateNormalize(NewStringLoader(`{"name": "a", "id": 1}`))
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+	result, err = ValidateAllOf([]*Schema{schema}, NewStringLoader(`{"name": "a", "id": 1}`))
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+	result, err = schema.ValidatePatched(NewStringLoader(`{"name": "a", "id": 1}`), []PatchOp{{Op: PATCH_ADD, Path: "/id", Value: 1}})
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+
+	_, err = NewSchemaWithOptions(NewStringLoader(`{"x-propertyOrder": "a"}`), extensions)
+	assert.NotNil(t, err)
+	_, err = NewSchemaWithOptions(NewStringLoader(`{"x-propertyOrder": ["a", "a"]}`), extensions)
+	assert.EqualError(t, err, "x-propertyOrder items must be unique")
+}
+
+func TestErrorMessageExtension(t *testing.T) {
+
+	extensions := SchemaLoaderOptions{EnableExtensions: true}
+
+	schema, err := NewSchemaWithOptions(NewStringLoader(`{
+		"type": "object",
+		"required": ["age"],
+		"properties": {
+			"age": {"type": "integer", "minimum": 18, "x-errorMessage": "must be an adult"},
+			"name": {"type": "string", "minLength": 1, "x-errorMessage": {"minLength": "must not be empty"}}
+		},
+		"additionalProperties": false,
+		"x-errorMessage": {"required": "age is mandatory"}
+	}`), extensions)
+	assert.Nil(t, err)
+
+	result, err := schema.Validate(NewStringLoader(`{"age": 12, "name": ""}`))
+	assert.Nil(t, err)
+	messages := map[string]string{}
+	for _, rerr := range result.Errors() {
+		messages[rerr.Context.String()+" "+rerr.Reason] = rerr.Message
+	}
+	assert.Equal(t, map[string]string{
+		"#/age minimum":    "must be an adult",
+		"#/name minLength": "must not be empty",
+	}, messages)
+
+	// the keywords without message keep theirs, nested subSchemas included
+	result, err = schema.Validate(NewStringLoader(`{"name": 1, "other": 0}`))
+	assert.Nil(t, err)
+	messages = map[string]string{}
+	for _, rerr := range result.Errors() {
+		messages[rerr.Context.String()+" "+rerr.Reason] = rerr.Message
+	}
+	assert.Equal(t, map[string]string{
+		"#/age required":               "age is mandatory",
+		"#/name type":                  "",
+		"#/other additionalProperties": "",
+	}, messages)
+	for _, rerr := range result.Errors() {
+		if rerr.Reason == KEY_REQUIRED {
+			assert.Equal(t, "#/age: age is mandatory", rerr.String())
+		}
+	}
+
+	// the innermost message wins, and the messages are kept by the compiled schemas
+	schema, err = NewSchemaWithOptions(NewStringLoader(`{
+		"allOf": [{"minimum": 1, "x-errorMessage": "inner"}],
+		"maximum": 0,
+		"x-errorMessage": "outer"
+	}`), extensions)
+	assert.Nil(t, err)
+	data, err := schema.MarshalCompiled()
+	assert.Nil(t, err)
+	compiled, err := LoadCompiled(data)
+	assert.Nil(t, err)
+	for _, s := range []*Schema{schema, compiled} {
+		result, err = s.Validate(NewStringLoader(`2`))
+		assert.Nil(t, err)
+		assert.Equal(t, "outer", result.Errors()[0].Message)
+		result, err = s.Validate(NewStringLoader(`-1`))
+		assert.Nil(t, err)
+		for _, rerr := range result.Errors() {
+			if rerr.Reason == KEY_MINIMUM {
+				assert.Equal(t, "inner", rerr.Message)
+			} else {
+				assert.Equal(t, "outer", rerr.Message)
+			}
+		}
+	}
+
+	// ignored without the extensions
+	schema, err = NewSchema(NewStringLoader(`{"minimum": 1, "x-errorMessage": "too small"}`))
+	assert.Nil(t, err)
+	result, err = schema.Validate(NewStringLoader(`0`))
+	assert.Nil(t, err)
+	assert.Equal(t, "", result.Errors()[0].Message)
+
+	_, err = NewSchemaWithOptions(NewStringLoader(`{"x-errorMessage": 1}`), extensions)
+	assert.EqualError(t, err, "x-errorMessage must be of a string or object of strings")
+	_, err = NewSchemaWithOptions(NewStringLoader(`{"x-errorMessage": {"type": 1}}`), extensions)
+	assert.NotNil(t, err)
+}
+
+func TestNumberFormatExtension(t *testing.T) {
+
+	extensions := SchemaLoaderOptions{EnableExtensions: true}
+
+	schema, err := NewSchema(NewStringLoader(`{"x-format": "int32"}`))
+	assert.Nil(t, err)
+	result, err := schema.Validate(NewStringLoader(`2147483648`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	schema, err = NewSchemaWithOptions(NewStringLoader(`{"x-format": "int32"}`), extensions)
+	assert.Nil(t, err)
+	for document, valid := range map[string]bool{`2147483647`: true, `-2147483648`: true, `2147483648`: false, `-2147483649`: false, `"2147483648"`: true} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	schema, err = NewSchemaWithOptions(NewStringLoader(`{"x-format": "int64"}`), extensions)
+	assert.Nil(t, err)
+	for document, valid := range map[string]bool{`9223372036854774784`: true, `-9223372036854775808`: true, `9223372036854775808`: false, `1e19`: false, `-1e19`: false} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	result, err = schema.Validate(NewStringLoader(`{"a": 1e20}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+	result, err = schema.Validate(NewStringLoader(`1e20`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, KEY_X_FORMAT, result.Errors()[0].Reason)
+		assert.Equal(t, NUMBER_FORMAT_INT64, result.Errors()[0].Requirement)
+	}
+
+	_, err = NewSchemaWithOptions(NewStringLoader(`{"x-format": "int128"}`), extensions)
+	assert.EqualError(t, err, `x-format must be of a "int32" or "int64"`)
+}
+
+func TestRejectNull(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"a": {},
+			"b": {"type": ["string", "null"]},
+			"c": {"enum": ["x", null]},
+			"d": {"anyOf": [{"type": "string"}, {"type": "null"}]},
+			"e": {"anyOf": [{"type": "string"}, {"minLength": 1}]}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	document := NewStringLoader(`{"a": null, "b": null, "c": null, "d": null, "e": null, "f": null}`)
+
+	result, err := schema.Validate(document)
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	result, err = schema.ValidateWithOptions(document, ValidateOptions{RejectNull: true})
+	assert.Nil(t, err)
+	var contexts []string
+	for _, resultError := range result.Errors() {
+		contexts = append(contexts, resultError.Context.String())
+	}
+	// both branches of e reject null
+	assert.ElementsMatch(t, []string{"#/a", "#/e"}, contexts)
+}
+
+func TestMaxErrorsPerPath(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"a": {"type": "string", "minLength": 5, "pattern": "^[a-z]+$", "enum": ["abcde"]},
+			"b": {"minimum": 3}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	document := NewStringLoader(`{"a": "AB", "b": 1}`)
+
+	result, err := schema.Validate(document)
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 4)
+
+	result, err = schema.ValidateWithOptions(document, ValidateOptions{MaxErrorsPerPath: 2})
+	assert.Nil(t, err)
+	counts := make(map[string]int)
+	for _, resultError := range result.Errors() {
+		counts[resultError.Context.String()]++
+	}
+	assert.Equal(t, map[string]int{"#/a": 2, "#/b": 1}, counts)
+}
+
+func TestValidateContent(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"raw": {"contentMediaType": "application/json"},
+			"encoded": {"contentEncoding": "base64", "contentMediaType": "application/json"},
+			"binary": {"contentEncoding": "base64"}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	// {"a":1} and {"a": in base64
+	valid := NewStringLoader(`{"raw": "[1, 2]", "encoded": "eyJhIjoxfQ==", "binary": "AAEC"}`)
+	invalid := NewStringLoader(`{"raw": "[1, 2", "encoded": "eyJhIjo=", "binary": "AAEC!"}`)
+
+	result, err := schema.Validate(invalid)
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	result, err = schema.ValidateWithOptions(valid, ValidateOptions{ValidateContent: true})
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	result, err = schema.ValidateWithOptions(invalid, ValidateOptions{ValidateContent: true})
+	assert.Nil(t, err)
+	reasons := make(map[string]string)
+	for _, resultError := range result.Errors() {
+		reasons[resultError.Context.String()] = resultError.Reason
+	}
+	assert.Equal(t, map[string]string{
+		"#/raw":     KEY_CONTENT_MEDIA_TYPE,
+		"#/encoded": KEY_CONTENT_MEDIA_TYPE,
+		"#/binary":  KEY_CONTENT_ENCODING,
+	}, reasons)
+}
+
+func TestValidateAllOf(t *testing.T) {
+
+	base, err := NewSchema(NewStringLoader(`{"type": "object", "required": ["id"]}`))
+	assert.Nil(t, err)
+	overlay, err := NewSchema(NewStringLoader(`{"properties": {"id": {"type": "integer"}, "name": {"maxLength": 3}}}`))
+	assert.Nil(t, err)
+
+	result, err := ValidateAllOf([]*Schema{base, overlay}, NewStringLoader(`{"id": 1, "name": "abc"}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	result, err = ValidateAllOf([]*Schema{base, overlay}, NewStringLoader(`{"name": "abcd"}`))
+	assert.Nil(t, err)
+	var reasons []string
+	for _, resultError := range result.Errors() {
+		reasons = append(reasons, resultError.Reason)
+	}
+	assert.ElementsMatch(t, []string{KEY_REQUIRED, KEY_MAX_LENGTH, KEY_ALL_OF}, reasons)
+
+	result, err = ValidateAllOf(nil, NewStringLoader(`{}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+}
+
+func TestValidateWithOverrides(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {"home": {"$ref": "#/definitions/address"}, "work": {"$ref": "#/definitions/address"}},
+		"definitions": {"address": {"type": "object", "required": ["zip"]}}
+	}`))
+	assert.Nil(t, err)
+
+	alternative, err := NewSchema(NewStringLoader(`{"type": "object", "required": ["zip", "city"]}`))
+	assert.Nil(t, err)
+
+	document := `{"home": {"zip": "75001"}, "work": {"zip": "69001", "city": "Lyon"}}`
+
+	result, err := schema.ValidateWithOverrides(NewStringLoader(document), map[string]*Schema{"#/definitions/address": alternative})
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, "#/home/city", result.Errors()[0].Context.String())
+	}
+
+	// the schema is left untouched
+	result, err = schema.Validate(NewStringLoader(document))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	_, err = schema.ValidateWithOverrides(NewStringLoader(document), map[string]*Schema{"#/definitions/unknown": alternative})
+	assert.EqualError(t, err, "No subSchema at #/definitions/unknown")
+
+	_, err = schema.ValidateWithOverrides(NewStringLoader(document), map[string]*Schema{"#/definitions/address": nil})
+	assert.EqualError(t, err, "No override for #/definitions/address")
+}
+
+func TestValidateKeyword(t *testing.T) {
+
+	for _, c := range []struct {
+		keyword     string
+		requirement interface{}
+		value       interface{}
+		valid       bool
+	}{
+		{KEY_MAXIMUM, 10, 10, true},
+		{KEY_MAXIMUM, 10, 12, false},
+		{KEY_MAXIMUM, 10, "12", true},
+		{KEY_PATTERN, "^[a-z]+$", "abc", true},
+		{KEY_PATTERN, "^[a-z]+$", "ABC", false},
+		{KEY_FORMAT, "email", "joe", false},
+		{KEY_REQUIRED, []string{"a"}, map[string]interface{}{}, false},
+		{KEY_X_MAX_DECIMALS, 2, 1.005, false},
+	} {
+		result, err := ValidateKeyword(c.keyword, c.requirement, c.value)
+		if assert.Nil(t, err, c.keyword) {
+			assert.Equal(t, c.valid, result.Valid(), c.keyword)
+		}
+		if !c.valid && assert.Len(t, result.Errors(), 1, c.keyword) {
+			assert.Equal(t, c.keyword, result.Errors()[0].Reason)
+		}
+	}
+
+	_, err := ValidateKeyword(KEY_PROPERTIES, map[string]interface{}{}, 1)
+	assert.EqualError(t, err, "Unknown keyword properties")
+
+	_, err = ValidateKeyword(KEY_MAXIMUM, "ten", 1)
+	assert.NotNil(t, err)
+}
+
+func TestPatternAndAdditionalProperties(t *testing.T) {
+
+	additionalProperties := map[string]string{
+		"absent": ``,
+		"true":   `, "additionalProperties": true`,
+		"false":  `, "additionalProperties": false`,
+		"schema": `, "additionalProperties": {"type": "string"}`,
+	}
+	// errors of {"id": 1, "x-a": 1, "x-b": "b", "other": 1}, by path
+	expected := map[string]map[string]string{
+		"absent": {"#/x-a": KEY_TYPE},
+		"true":   {"#/x-a": KEY_TYPE},
+		"false":  {"#/x-a": KEY_TYPE, "#/other": KEY_ADDITIONAL_PROPERTIES},
+		"schema": {"#/x-a": KEY_TYPE, "#/other": KEY_TYPE},
+	}
+
+	for name, keyword := range additionalProperties {
+		schema, err := NewSchema(NewStringLoader(`{
+			"properties": {"id": {"type": "integer"}},
+			"patternProperties": {"^x-": {"type": "string"}}` + keyword + `
+		}`))
+		assert.Nil(t, err)
+
+		result, err := schema.Validate(NewStringLoader(`{"id": 1, "x-a": 1, "x-b": "b", "other": 1}`))
+		assert.Nil(t, err)
+		reasons := make(map[string]string)
+		for _, resultError := range result.Errors() {
+			reasons[resultError.Context.String()] = resultError.Reason
+		}
+		assert.Equal(t, expected[name], reasons, name)
+		assert.Len(t, result.Errors(), len(expected[name]), name)
+	}
+}
+
+func TestUseTitleInErrors(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"email": {"title": "Email address", "type": "string"},
+			"age": {"$ref": "#/definitions/age"},
+			"tags": {"items": {"type": "string"}},
+			"name": {"title": "Full name"}
+		},
+		"required": ["name"],
+		"definitions": {"age": {"title": "Age", "minimum": 0}}
+	}`))
+	assert.Nil(t, err)
+
+	document := NewStringLoader(`{"email": 1, "age": -1, "tags": [1]}`)
+
+	result, err := schema.Validate(document)
+	assert.Nil(t, err)
+	for _, resultError := range result.Errors() {
+		assert.Equal(t, "", resultError.Title)
+	}
+
+	result, err = schema.ValidateWithOptions(document, ValidateOptions{UseTitleInErrors: true})
+	assert.Nil(t, err)
+	titles := make(map[string]string)
+	for _, resultError := range result.Errors() {
+		titles[resultError.Context.String()] = resultError.Title
+	}
+	assert.Equal(t, map[string]string{
+		"#/email":  "Email address",
+		"#/age":    "Age",
+		"#/tags/0": "",
+		"#/name":   "Full name",
+	}, titles)
+
+	for _, resultError := range result.Errors() {
+		if resultError.Reason == KEY_TYPE && resultError.Title != "" {
+			assert.Equal(t, "Email address: type,string", resultError.String())
+		}
+	}
+}
+
+func TestNonFiniteNumbers(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"items": {"maximum": 10}}`))
+	assert.Nil(t, err)
+
+	// the Go loader goes through encoding/json, which refuses them
+	_, err = schema.Validate(NewGoLoader([]interface{}{math.NaN()}))
+	assert.NotNil(t, err)
+
+	for _, value := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		result := schema.validateDocument([]interface{}{1.0, value}, ValidateOptions{})
+		if assert.Len(t, result.Errors(), 1) {
+			assert.Equal(t, "#/1", result.Errors()[0].Context.String())
+			assert.Equal(t, KEY_TYPE, result.Errors()[0].Reason)
+			assert.Equal(t, STRING_FINITE_NUMBER, result.Errors()[0].Requirement)
+		}
+	}
+}
+
+func TestMapOfWidgets(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"type": "object",
+		"propertyNames": {"pattern": "^[a-z][a-z0-9-]*$", "maxLength": 8},
+		"additionalProperties": {
+			"type": "object",
+			"properties": {"kind": {"enum": ["button", "slider"]}, "size": {"type": "integer"}},
+			"required": ["kind"]
+		},
+		"minProperties": 2
+	}`))
+	assert.Nil(t, err)
+
+	result, err := schema.Validate(NewStringLoader(`{"ok": {"kind": "button"}, "volume-1": {"kind": "slider", "size": 3}}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	result, err = schema.Validate(NewStringLoader(`{"Bad": {"kind": "knob"}}`))
+	assert.Nil(t, err)
+	type found struct{ context, reason string }
+	var errs []found
+	for _, resultError := range result.Errors() {
+		errs = append(errs, found{resultError.Context.String(), resultError.Reason})
+		if resultError.Reason == KEY_PATTERN {
+			assert.Equal(t, "Bad", resultError.Details[KEY_PROPERTY_NAMES])
+		} else {
+			assert.Nil(t, resultError.Details[KEY_PROPERTY_NAMES])
+		}
+	}
+	assert.ElementsMatch(t, []found{
+		{"#", KEY_MIN_PROPERTIES},
+		{"#/Bad", KEY_PATTERN},
+		{"#/Bad/kind", KEY_ENUM},
+	}, errs)
+
+	result, err = schema.Validate(NewStringLoader(`{"longer-name": {"kind": "button"}, "b": {}}`))
+	assert.Nil(t, err)
+	errs = nil
+	for _, resultError := range result.Errors() {
+		errs = append(errs, found{resultError.Context.String(), resultError.Reason})
+	}
+	assert.ElementsMatch(t, []found{
+		{"#/longer-name", KEY_MAX_LENGTH},
+		{"#/b/kind", KEY_REQUIRED},
+	}, errs)
+}
+
+func TestCoveredSchemas(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"pet": {"oneOf": [{"$ref": "#/definitions/cat"}, {"$ref": "#/definitions/dog"}]},
+			"name": {"type": "string"}
+		},
+		"definitions": {
+			"cat": {"properties": {"meows": {"type": "boolean"}}, "required": ["meows"]},
+			"dog": {"properties": {"barks": {"type": "boolean"}}, "required": ["barks"]}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	document := NewStringLoader(`{"pet": {"meows": true}, "name": 1}`)
+
+	result, err := schema.Validate(document)
+	assert.Nil(t, err)
+	assert.Empty(t, result.CoveredSchemas())
+
+	result, err = schema.ValidateWithOptions(document, ValidateOptions{TrackCoverage: true})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{
+		"#/definitions/cat",
+		"#/definitions/cat/properties/meows",
+		"#/properties/pet",
+		"#/properties/pet/oneOf/0",
+	}, result.CoveredSchemas())
+}
+
+func TestEvaluatedProperties(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"name": {"type": "string"},
+			"items": {"items": {"properties": {"id": {}}, "patternProperties": {"^x-": {}}, "additionalProperties": {"type": "integer"}}},
+			"closed": {"properties": {"a": {}}, "additionalProperties": false},
+			"open": {"additionalProperties": true},
+			"undeclared": {"properties": {"a": {}}}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	document := NewStringLoader(`{
+		"name": "n",
+		"items": [{"id": 1, "x-a": 2, "b": "3"}],
+		"closed": {"a": 1, "b": 2},
+		"open": {"a": 1},
+		"undeclared": {"b": 1}
+	}`)
+
+	result, err := schema.ValidateWithOptions(document, ValidateOptions{TrackEvaluatedProperties: true})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"closed", "items", "name", "open", "undeclared"}, result.EvaluatedProperties(""))
+	assert.Equal(t, []string{"b", "id", "x-a"}, result.EvaluatedProperties("/items/0"))
+	assert.Equal(t, []string{"b", "id", "x-a"}, result.EvaluatedProperties("#/items/0"))
+	assert.Equal(t, []string{"a"}, result.EvaluatedProperties("/closed"))
+	assert.Equal(t, []string{"a"}, result.EvaluatedProperties("/open"))
+	assert.Empty(t, result.EvaluatedProperties("/undeclared"))
+	assert.Empty(t, result.EvaluatedProperties("/unknown"))
+
+	// only when asked for
+	result, err = schema.Validate(document)
+	assert.Nil(t, err)
+	assert.Empty(t, result.EvaluatedProperties(""))
+}
+
+func TestAppliedSchema(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"definitions": {
+			"number": {"type": "number"},
+			"text": {"type": "string", "minLength": 2}
+		},
+		"properties": {
+			"items": {"items": {"anyOf": [{"$ref": "#/definitions/number"}, {"$ref": "#/definitions/text"}]}},
+			"both": {"allOf": [{"type": "object"}, {"required": ["a"]}]}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	result, err := schema.ValidateWithOptions(NewStringLoader(`{"items": [1, "ab", "a"], "both": {"a": 1}}`), ValidateOptions{TrackAppliedSchemas: true})
+	assert.Nil(t, err)
+
+	applied, ok := result.AppliedSchema("/items/0")
+	assert.True(t, ok)
+	assert.Equal(t, "#/definitions/number", applied.Location())
+
+	applied, ok = result.AppliedSchema("#/items/1")
+	assert.True(t, ok)
+	assert.Equal(t, "#/definitions/text", applied.Location())
+
+	// "a" is too short for the text, and is not a number
+	_, ok = result.AppliedSchema("/items/2")
+	assert.False(t, ok)
+
+	applied, ok = result.AppliedSchema("/both")
+	assert.True(t, ok)
+	assert.Equal(t, "#/properties/both/allOf/1", applied.Location())
+
+	applied, ok = result.AppliedSchema("/both/a")
+	assert.False(t, ok)
+
+	// the root is not valid, "/items/2" failing
+	_, ok = result.AppliedSchema("")
+	assert.False(t, ok)
+
+	// only when asked for
+	result, err = schema.Validate(NewStringLoader(`{"items": [1]}`))
+	assert.Nil(t, err)
+	_, ok = result.AppliedSchema("/items/0")
+	assert.False(t, ok)
+}
+
+func TestMultipleOfLargeIntegers(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"multipleOf": 1024}`))
+	assert.Nil(t, err)
+
+	for document, valid := range map[string]bool{`4611686018427387904`: true, `4611686018427387904000`: true, `3`: false, `1024.5`: false} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+}
+
+func TestStrictSchema(t *testing.T) {
+
+	strict := SchemaLoaderOptions{StrictSchema: true}
+
+	_, err := NewSchemaWithOptions(NewStringLoader(`{"properties": {"a": {"enum": []}}}`), strict)
+	assert.EqualError(t, err, "enum is empty at #/properties/a")
+
+	schema, err := NewSchemaWithOptions(NewStringLoader(`{"required": [], "properties": {"a": {"required": ["b"]}, "c": {"required": []}}}`), strict)
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"required is empty at #", "required is empty at #/properties/c"}, schema.Warnings())
+
+	// not checked by default
+	schema, err = NewSchema(NewStringLoader(`{"required": [], "enum": []}`))
+	assert.Nil(t, err)
+	assert.Empty(t, schema.Warnings())
+}
+
+func TestAdditionalPropertiesRequirement(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {"b": {}, "a": {}},
+		"patternProperties": {"^x-": {}, "^[0-9]+$": {}},
+		"additionalProperties": false
+	}`))
+	assert.Nil(t, err)
+
+	full := ValidateOptions{AdditionalPropertyErrorDetail: ALLOWED_PROPERTIES_FULL}
+	document := NewStringLoader(`{"a": 1, "x-b": 2, "c": 3}`)
+
+	result, err := schema.ValidateWithOptions(document, full)
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, "#/c", result.Errors()[0].Context.String())
+		assert.Equal(t, map[string][]string{
+			KEY_PROPERTIES:         {"a", "b"},
+			KEY_PATTERN_PROPERTIES: {"^[0-9]+$", "^x-"},
+		}, result.Errors()[0].Requirement)
+	}
+
+	// the counts by default, nothing at all on demand
+	result, err = schema.Validate(document)
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, map[string]int{KEY_PROPERTIES: 2, KEY_PATTERN_PROPERTIES: 2}, result.Errors()[0].Requirement)
+		assert.Equal(t, "#/c: additionalProperties,map[patternProperties:2 properties:2]", result.Errors()[0].String())
+	}
+	result, err = schema.ValidateWithOptions(document, ValidateOptions{AdditionalPropertyErrorDetail: ALLOWED_PROPERTIES_NONE})
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Nil(t, result.Errors()[0].Requirement)
+		assert.Equal(t, "#/c: additionalProperties", result.Errors()[0].String())
+	}
+
+	schema, err = NewSchema(NewStringLoader(`{"additionalProperties": false}`))
+	assert.Nil(t, err)
+	result, err = schema.ValidateWithOptions(NewStringLoader(`{"c": 3}`), full)
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, map[string][]string{KEY_PROPERTIES: {}, KEY_PATTERN_PROPERTIES: {}}, result.Errors()[0].Requirement)
+	}
+}
+
+func TestAdditionalPropertiesValue(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"properties": {"a": {}}, "additionalProperties": false}`))
+	assert.Nil(t, err)
+
+	result, err := schema.Validate(NewStringLoader(`{"a": 1, "b": "sent", "c": {"d": [true]}, "e": null}`))
+	assert.Nil(t, err)
+	values := make(map[string]interface{})
+	for _, rerr := range result.Errors() {
+		assert.Equal(t, KEY_ADDITIONAL_PROPERTIES, rerr.Reason)
+		values[rerr.Context.String()] = rerr.Value
+	}
+	assert.Equal(t, map[string]interface{}{
+		"#/b": "sent",
+		"#/c": map[string]interface{}{"d": []interface{}{true}},
+		"#/e": nil,
+	}, values)
+
+	// with a schema, the errors are those of the value against it
+	schema, err = NewSchema(NewStringLoader(`{"additionalProperties": {"type": "integer"}}`))
+	assert.Nil(t, err)
+	result, err = schema.Validate(NewStringLoader(`{"b": "sent"}`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, "#/b", result.Errors()[0].Context.String())
+		assert.Equal(t, "sent", result.Errors()[0].Value)
+	}
+}
+
+func TestIntegerRejectsBooleans(t *testing.T) {
+
+	cases := []struct {
+		schema   string
+		document string
+		valid    bool
+	}{
+		{`{"type": "integer"}`, `true`, false},
+		{`{"type": "integer"}`, `false`, false},
+		{`{"type": "number"}`, `true`, false},
+		{`{"type": ["integer", "boolean"]}`, `true`, true},
+		{`{"type": "integer"}`, `1`, true},
+		{`{"enum": [1]}`, `true`, false},
+		{`{"enum": [0]}`, `false`, false},
+		{`{"enum": [true]}`, `1`, false},
+		{`{"minimum": 2}`, `true`, true}, // numeric keywords ignore other types
+		{`{"properties": {"a": {"type": "integer"}}}`, `{"a": true}`, false},
+		{`{"items": {"type": "integer", "maximum": 1}}`, `[0, true]`, false},
+	}
+
+	for _, c := range cases {
+		result, err := Validate(NewStringLoader(c.schema), NewStringLoader(c.document))
+		if assert.Nil(t, err) {
+			assert.Equal(t, c.valid, result.Valid(), "%s against %s", c.document, c.schema)
+		}
+	}
+
+	// Go values are not coerced either
+	result, err := Validate(NewStringLoader(`{"type": "integer"}`), NewGoLoader(true))
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+
+	// nor are the booleans of the schema
+	_, err = NewSchema(NewStringLoader(`{"minimum": true}`))
+	assert.NotNil(t, err)
+}
+
+func TestNormalizeUnicode(t *testing.T) {
+
+	precomposed := "caf\u00e9" // é as a single code point
+	decomposed := "cafe\u0301" // e followed by a combining acute accent
+
+	schema, err := NewSchema(NewStringLoader(`{"maxLength": 4, "pattern": "^caf\u00e9$"}`))
+	assert.Nil(t, err)
+
+	cases := []struct {
+		normalization UnicodeNormalization
+		value         string
+		nbErrors      int
+	}{
+		{NORMALIZE_NONE, precomposed, 0},
+		{NORMALIZE_NONE, decomposed, 2}, // 5 runes, and the pattern does not match
+		{NORMALIZE_NFC, precomposed, 0},
+		{NORMALIZE_NFC, decomposed, 0},
+		{NORMALIZE_NFD, precomposed, 2},
+		{NORMALIZE_NFD, decomposed, 2},
+	}
+
+	for _, c := range cases {
+		result, err := schema.ValidateWithOptions(NewGoLoader(c.value), ValidateOptions{NormalizeUnicode: c.normalization})
+		assert.Nil(t, err)
+		assert.Len(t, result.Errors(), c.nbErrors, "%q normalized with %d", c.value, c.normalization)
+	}
+
+	// the errors keep the value of the document
+	result, err := schema.ValidateWithOptions(NewGoLoader(precomposed), ValidateOptions{NormalizeUnicode: NORMALIZE_NFD})
+	assert.Nil(t, err)
+	for _, rerr := range result.Errors() {
+		assert.Equal(t, precomposed, rerr.Value)
+		if rerr.Reason == KEY_PATTERN {
+			assert.Equal(t, precomposed, rerr.Details[STRING_VALUE])
+		}
+	}
+	schema, err = NewSchema(NewStringLoader(`{"pattern": "^x$"}`))
+	assert.Nil(t, err)
+	result, err = schema.ValidateWithOptions(NewGoLoader(decomposed), ValidateOptions{NormalizeUnicode: NORMALIZE_NFC})
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, decomposed, result.Errors()[0].Value)
+		assert.Equal(t, decomposed, result.Errors()[0].Details[STRING_VALUE])
+	}
+
+	// with decomposed characters in the schema
+	schema, err = NewSchema(NewStringLoader(`{"minLength": 5, "pattern": "^cafe\u0301$"}`))
+	assert.Nil(t, err)
+	result, err = schema.ValidateWithOptions(NewGoLoader(precomposed), ValidateOptions{NormalizeUnicode: NORMALIZE_NFD})
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+}
+
+func TestStrictRequired(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"properties": {"a": {"required": ["b"]}}}`))
+	assert.Nil(t, err)
+
+	for document, valid := range map[string]bool{`{"a": {"b": 1}}`: true, `{"a": {}}`: false, `{"a": 1}`: true, `{"a": null}`: true, `{"a": []}`: true} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	strict := ValidateOptions{StrictRequired: true}
+	for document, valid := range map[string]bool{`{"a": {"b": 1}}`: true, `{"a": {}}`: false, `{"a": 1}`: false, `{"a": null}`: false, `{"a": []}`: false, `{}`: true} {
+		result, err := schema.ValidateWithOptions(NewStringLoader(document), strict)
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	result, err := schema.ValidateWithOptions(NewStringLoader(`{"a": "b"}`), strict)
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		rerr := result.Errors()[0]
+		assert.Equal(t, "#/a", rerr.Context.String())
+		assert.Equal(t, KEY_TYPE, rerr.Reason)
+		assert.Equal(t, TYPE_OBJECT, rerr.Requirement)
+		assert.Equal(t, []string{"b"}, rerr.Details[KEY_REQUIRED])
+	}
+
+	// the subSchemas having a type are left to it
+	schema, err = NewSchema(NewStringLoader(`{"type": ["string", "object"], "required": ["b"]}`))
+	assert.Nil(t, err)
+	result, err = schema.ValidateWithOptions(NewStringLoader(`"a"`), strict)
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+	result, err = schema.ValidateWithOptions(NewStringLoader(`1`), strict)
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 1)
+}
+
+func TestDeadline(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"items": {"type": "string"}}`))
+	assert.Nil(t, err)
+
+	var items []string
+	for i := 0; i < 2000; i++ {
+		items = append(items, "1")
+	}
+	document := "[" + strings.Join(items, ",") + "]"
+
+	result, err := schema.ValidateWithOptions(NewStringLoader(document), ValidateOptions{Deadline: time.Now().Add(time.Hour)})
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 2000)
+
+	// the clock is read before the first node
+	result, err = schema.ValidateWithOptions(NewStringLoader(document), ValidateOptions{Deadline: time.Now().Add(-time.Second)})
+	assert.Equal(t, ErrDeadlineExceeded, err)
+	if assert.NotNil(t, result) {
+		assert.Empty(t, result.Errors())
+	}
+
+	// then every deadlineCheckInterval nodes
+	check := &deadlineCheck{deadline: time.Now().Add(50 * time.Millisecond)}
+	assert.False(t, check.isExceeded())
+	time.Sleep(100 * time.Millisecond)
+	for i := 1; i < deadlineCheckInterval; i++ {
+		assert.False(t, check.isExceeded())
+	}
+	assert.True(t, check.isExceeded())
+	assert.True(t, check.isExceeded())
+
+	var none *deadlineCheck
+	assert.False(t, none.isExceeded())
+}
+
+func TestDisallowEmpty(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"type": ["object", "null", "string"]}`))
+	assert.Nil(t, err)
+
+	for _, document := range []JSONLoader{NewStringLoader(`null`), NewStringLoader(`""`), NewStringLoader(""), NewStringLoader(" \n"), NewGoLoader(nil)} {
+		result, err := schema.ValidateWithOptions(document, ValidateOptions{DisallowEmpty: true})
+		assert.Equal(t, ErrEmptyDocument, err)
+		assert.Nil(t, result)
+	}
+
+	for _, document := range []JSONLoader{NewStringLoader(`{}`), NewStringLoader(`" "`), NewGoLoader(map[string]interface{}{})} {
+		result, err := schema.ValidateWithOptions(document, ValidateOptions{DisallowEmpty: true})
+		assert.Nil(t, err)
+		assert.True(t, result.Valid())
+	}
+
+	// null is valid by default
+	result, err := schema.Validate(NewStringLoader(`null`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+}
+
+func TestRootReferenceWithDefinitions(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"$ref": "#/definitions/person",
+		"definitions": {
+			"person": {"type": "object", "properties": {"age": {"$ref": "#/definitions/age"}}, "required": ["age"]},
+			"age": {"type": "integer", "minimum": 0}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	for document, valid := range map[string]bool{`{"age": 1}`: true, `{"age": -1}`: false, `{"age": "1"}`: false, `{}`: false, `[]`: false} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	// a definition referencing the root, itself a reference, used to recurse forever
+	schema, err = NewSchema(NewStringLoader(`{
+		"$ref": "#/definitions/node",
+		"definitions": {
+			"node": {"type": "object", "properties": {"value": {"type": "integer"}, "next": {"$ref": "#"}}}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	result, err := schema.Validate(NewStringLoader(`{"value": 1, "next": {"value": 2, "next": {"value": "3"}}}`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, "#/next/next/value", result.Errors()[0].Context.String())
+	}
+	result, err = schema.Validate(NewStringLoader(`{"next": {"next": {}}}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+}
+
+func TestAnyFormatExtension(t *testing.T) {
+
+	extensions := SchemaLoaderOptions{EnableExtensions: true}
+
+	schema, err := NewSchemaWithOptions(NewStringLoader(`{"x-anyFormat": ["email", "ipv4", "unknown"]}`), extensions)
+	assert.Nil(t, err)
+	for document, valid := range map[string]bool{`"a@b.c"`: true, `"127.0.0.1"`: true, `"a"`: false, `""`: false, `1`: true} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	result, err := schema.Validate(NewStringLoader(`"a"`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, KEY_X_ANY_FORMAT, result.Errors()[0].Reason)
+		assert.Equal(t, []string{"email", "ipv4", "unknown"}, result.Errors()[0].Requirement)
+		assert.ErrorIs(t, result.Err(), ErrAnyFormat)
+	}
+
+	// ignored without the extensions
+	schema, err = NewSchema(NewStringLoader(`{"x-anyFormat": ["email"]}`))
+	assert.Nil(t, err)
+	result, err = schema.Validate(NewStringLoader(`"a"`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	_, err = NewSchemaWithOptions(NewStringLoader(`{"x-anyFormat": []}`), extensions)
+	assert.EqualError(t, err, `x-anyFormat must be of an array of strings`)
+	_, err = NewSchemaWithOptions(NewStringLoader(`{"x-anyFormat": ["email", 1]}`), extensions)
+	assert.EqualError(t, err, `x-anyFormat items must be string`)
+}
+
+func TestBooleanItems(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"properties": {"none": {"type": "array", "items": false}, "any": {"items": true}}}`))
+	assert.Nil(t, err)
+
+	for document, valid := range map[string]bool{`{"none": []}`: true, `{"none": [1]}`: false, `{"none": [null]}`: false, `{"any": [1, "a", null]}`: true, `{"any": []}`: true} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	result, err := schema.Validate(NewStringLoader(`{"none": [1, "a"]}`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 2) {
+		assert.Equal(t, "#/none/0", result.Errors()[0].Context.String())
+		assert.Equal(t, "#/none/1", result.Errors()[1].Context.String())
+		assert.Equal(t, KEY_ITEMS, result.Errors()[1].Reason)
+		assert.Equal(t, "a", result.Errors()[1].Value)
+	}
+
+	// additionalItems: false after a tuple forbids the extra items
+	schema, err = NewSchema(NewStringLoader(`{"items": [{"type": "integer"}], "additionalItems": false}`))
+	assert.Nil(t, err)
+	for document, valid := range map[string]bool{`[1]`: true, `[1, 2]`: false} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	marshaled, err := json.Marshal(marshalSubSchema(schema.rootSchema))
+	assert.Nil(t, err)
+	assert.Equal(t, `{"additionalItems":false,"items":[{"type":"integer"}]}`, string(marshaled))
+
+	_, err = NewSchema(NewStringLoader(`{"items": 1}`))
+	assert.EqualError(t, err, `items must be of type boolean/schema/array of schemas`)
+}
+
+func TestTupleItems(t *testing.T) {
+
+	tuple := `"items": [{"type": "string"}, {"type": "integer"}, {"type": "boolean"}]`
+
+	reasons := func(schemaJSON string, document string) []string {
+		schema, err := NewSchema(NewStringLoader(schemaJSON))
+		assert.Nil(t, err)
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		var reasons []string
+		for _, rerr := range result.Errors() {
+			reasons = append(reasons, rerr.Context.String()+" "+rerr.Reason)
+		}
+		return reasons
+	}
+
+	// exact length
+	assert.Nil(t, reasons(`{`+tuple+`}`, `["a", 1, true]`))
+	assert.Equal(t, []string{"#/0 type", "#/2 type"}, reasons(`{`+tuple+`}`, `[1, 1, "true"]`))
+
+	// shorter than the tuple, the present items are validated
+	assert.Nil(t, reasons(`{`+tuple+`}`, `["a"]`))
+	assert.Nil(t, reasons(`{`+tuple+`}`, `[]`))
+	assert.Equal(t, []string{"#/1 type"}, reasons(`{`+tuple+`}`, `["a", "1"]`))
+
+	// longer than the tuple, the additional items are allowed by default
+	assert.Nil(t, reasons(`{`+tuple+`}`, `["a", 1, true, null, {}]`))
+	assert.Equal(t, []string{"#/1 type"}, reasons(`{`+tuple+`}`, `["a", 1.5, true, null]`))
+
+	// longer than the tuple with additionalItems: false, the array fails
+	assert.Nil(t, reasons(`{`+tuple+`, "additionalItems": false}`, `["a", 1, true]`))
+	assert.Equal(t, []string{"# additionalItems"}, reasons(`{`+tuple+`, "additionalItems": false}`, `["a", 1, true, null]`))
+	assert.Equal(t, []string{"#/0 type", "# additionalItems"}, reasons(`{`+tuple+`, "additionalItems": false}`, `[0, 1, true, null]`))
+
+	// longer than the tuple with an additionalItems schema, each additional item
+	// is validated at its own position
+	schemaJSON := `{` + tuple + `, "additionalItems": {"type": "null"}}`
+	assert.Nil(t, reasons(schemaJSON, `["a", 1, true, null, null]`))
+	assert.Equal(t, []string{"#/2 type", "#/4 type", "#/5 type"}, reasons(schemaJSON, `["a", 1, 0, null, 0, "b"]`))
+
+	// additionalItems does not apply to a single items schema
+	assert.Nil(t, reasons(`{"items": {"type": "string"}, "additionalItems": false}`, `["a", "b", "c"]`))
+}
+
+func TestTypeErrorDetails(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"properties": {"n": {"type": ["integer", "string"]}}}`))
+	assert.Nil(t, err)
+
+	for document, actual := range map[string]string{
+		`{"n": null}`: TYPE_NULL,
+		`{"n": []}`:   TYPE_ARRAY,
+		`{"n": {}}`:   TYPE_OBJECT,
+		`{"n": true}`: TYPE_BOOLEAN,
+		`{"n": 1.5}`:  TYPE_NUMBER,
+		`{"n": "a"}`:  "",
+		`{"n": 2}`:    "",
+		`{"n": 2.0}`:  "",
+	} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		if actual == "" {
+			assert.True(t, result.Valid(), document)
+			continue
+		}
+		if assert.Len(t, result.Errors(), 1, document) {
+			details := result.Errors()[0].Details
+			assert.Equal(t, []string{TYPE_INTEGER, TYPE_STRING}, details[STRING_EXPECTED], document)
+			assert.Equal(t, actual, details[STRING_ACTUAL], document)
+		}
+	}
+
+	schema, err = NewSchema(NewStringLoader(`{"type": "array"}`))
+	assert.Nil(t, err)
+	result, err := schema.Validate(NewStringLoader(`"a"`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, TYPE_STRING, result.Errors()[0].Details[STRING_ACTUAL])
+	}
+	result, err = schema.Validate(NewStringLoader(`4`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, []string{TYPE_ARRAY}, result.Errors()[0].Details[STRING_EXPECTED])
+		assert.Equal(t, TYPE_INTEGER, result.Errors()[0].Details[STRING_ACTUAL])
+	}
+
+	// the options reporting type errors
+	schema, err = NewSchema(NewStringLoader(`{"required": ["a"]}`))
+	assert.Nil(t, err)
+	result, err = schema.ValidateWithOptions(NewStringLoader(`null`), ValidateOptions{RejectNull: true})
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, []string{}, result.Errors()[0].Details[STRING_EXPECTED])
+		assert.Equal(t, TYPE_NULL, result.Errors()[0].Details[STRING_ACTUAL])
+	}
+	result, err = schema.ValidateWithOptions(NewStringLoader(`"a"`), ValidateOptions{StrictRequired: true})
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, []string{TYPE_OBJECT}, result.Errors()[0].Details[STRING_EXPECTED])
+		assert.Equal(t, TYPE_STRING, result.Errors()[0].Details[STRING_ACTUAL])
+	}
+
+	result = schema.validateDocument(math.NaN(), ValidateOptions{})
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, STRING_FINITE_NUMBER, result.Errors()[0].Requirement)
+		assert.Equal(t, TYPE_NUMBER, result.Errors()[0].Details[STRING_ACTUAL])
+	}
+}
+
+func TestPropertyDescent(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"mistyped": {"type": "array", "properties": {"a": {"type": "string"}}},
+			"short": {"minProperties": 3, "properties": {"a": {"type": "string"}}, "patternProperties": {"^b": {"type": "string"}}}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	document := `{"mistyped": {"a": 1}, "short": {"a": 1, "b": 2}}`
+
+	contexts := func(result *Result) []string {
+		var contexts []string
+		for _, rerr := range result.Errors() {
+			contexts = append(contexts, rerr.Context.String()+" "+rerr.Reason)
+		}
+		return contexts
+	}
+
+	// by default, only a type error stops the descent
+	result, err := schema.Validate(NewStringLoader(document))
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"#/mistyped type", "#/short minProperties", "#/short/a type", "#/short/b type"}, contexts(result))
+
+	result, err = schema.ValidateWithOptions(NewStringLoader(document), ValidateOptions{PropertyDescent: DESCENT_ALWAYS})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"#/mistyped type", "#/mistyped/a type", "#/short minProperties", "#/short/a type", "#/short/b type"}, contexts(result))
+
+	result, err = schema.ValidateWithOptions(NewStringLoader(document), ValidateOptions{PropertyDescent: DESCENT_ON_VALID_OBJECT})
+	assert.Nil(t, err)
+	assert.ElementsMatch(t, []string{"#/mistyped type", "#/short minProperties"}, contexts(result))
+
+	// the properties of valid objects are still validated
+	result, err = schema.ValidateWithOptions(NewStringLoader(`{"short": {"a": 1, "b": "2", "c": 3}}`), ValidateOptions{PropertyDescent: DESCENT_ON_VALID_OBJECT})
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"#/short/a type"}, contexts(result))
+}
+
+func TestIgnoreRequired(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"required": ["name", "address"],
+		"properties": {
+			"name": {"type": "string"},
+			"address": {"type": "object", "required": ["city"], "properties": {"zip": {"type": "string"}}}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	result, err := schema.ValidateWithOptions(NewStringLoader(`{"name": "Ada", "address": {}}`), ValidateOptions{IgnoreRequired: true})
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	// the present properties are still validated
+	result, err = schema.ValidateWithOptions(NewStringLoader(`{"address": {"zip": 75001}}`), ValidateOptions{IgnoreRequired: true})
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, "#/address/zip", result.Errors()[0].Context.String())
+		assert.Equal(t, KEY_TYPE, result.Errors()[0].Reason)
+	}
+
+	result, err = schema.Validate(NewStringLoader(`{"address": {}}`))
+	assert.Nil(t, err)
+	assert.Len(t, result.Errors(), 2)
+}
+
+func TestNumericStrings(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {
+			"id": {"type": "integer", "minimum": 1},
+			"price": {"type": "number", "maximum": 100},
+			"code": {"type": ["string", "integer"], "maxLength": 2},
+			"name": {"type": "string"}
+		}
+	}`))
+	assert.Nil(t, err)
+
+	for document, valid := range map[string]bool{
+		`{"id": "9007199254740993"}`: true,
+		`{"id": 12}`:                 true,
+		`{"id": "0"}`:                false,
+		`{"id": "1.5"}`:              false,
+		`{"id": "0x10"}`:             false,
+		`{"id": " 12"}`:              false,
+		`{"id": "Inf"}`:              false,
+		`{"price": "-1.5e1"}`:        true,
+		`{"price": "101"}`:           false,
+		`{"code": "123"}`:            false,
+		`{"name": "12"}`:             true,
+	} {
+		result, err := schema.ValidateWithOptions(NewStringLoader(document), ValidateOptions{NumericStrings: true})
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	result, err := schema.Validate(NewStringLoader(`{"id": "9007199254740993"}`))
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+}
+
+func TestCaseInsensitiveProperties(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {"userId": {"type": "integer"}, "id": {"type": "string"}, "ID": {"type": "integer"}},
+		"required": ["userId"],
+		"additionalProperties": false
+	}`))
+	assert.Nil(t, err)
+
+	reasons := func(document string, options ValidateOptions) []string {
+		result, err := schema.ValidateWithOptions(NewStringLoader(document), options)
+		assert.Nil(t, err)
+		var reasons []string
+		for _, rerr := range result.Errors() {
+			reasons = append(reasons, rerr.Context.String()+" "+rerr.Reason)
+		}
+		return reasons
+	}
+
+	insensitive := ValidateOptions{CaseInsensitiveProperties: true}
+
+	assert.Nil(t, reasons(`{"UserId": 1}`, insensitive))
+	assert.Equal(t, []string{"#/USERID type"}, reasons(`{"USERID": "1"}`, insensitive))
+	assert.Equal(t, []string{"#/UserId type", "#/userid type"}, reasons(`{"UserId": "1", "userid": "2", "userId": 3}`, insensitive))
+
+	// the truly unknown keys are still additional
+	assert.Equal(t, []string{"#/name additionalProperties"}, reasons(`{"userID": 1, "name": "a"}`, insensitive))
+
+	// a key matches the property spelled as it is, then the first in byte order
+	assert.Nil(t, reasons(`{"userId": 1, "id": "a", "ID": 1, "Id": 2}`, insensitive))
+	assert.Equal(t, []string{"#/Id type"}, reasons(`{"userId": 1, "Id": "b"}`, insensitive))
+
+	// case-sensitive by default
+	assert.ElementsMatch(t, []string{"#/userId required", "#/UserId additionalProperties"}, reasons(`{"UserId": 1}`, ValidateOptions{}))
+}
+
+func TestNumericEpsilon(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"enum": [0.3, 1, "0.3", [0.3]]}`))
+	assert.Nil(t, err)
+
+	a, b := 0.1, 0.2
+	options := ValidateOptions{NumericEpsilon: 1e-9}
+
+	for document, valid := range map[interface{}]bool{a + b: true, 1.0000000001: true, 0.31: false, "0.3": true, "0.30": false} {
+		result, err := schema.ValidateWithOptions(NewGoLoader(document), options)
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	// nested numbers are compared exactly
+	result, err := schema.ValidateWithOptions(NewGoLoader([]interface{}{a + b}), options)
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+
+	// exact by default
+	result, err = schema.Validate(NewGoLoader(a + b))
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+}
+
+func TestSuggestEnum(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"enum": ["active", "inactive", "pending", 1, null]}`))
+	assert.Nil(t, err)
+
+	suggestions := map[string]interface{}{
+		`"activ"`:   "active",
+		`"Active"`:  "active",
+		`"pendign"`: "pending",
+		`"inactiv"`: "inactive",
+		`"closed"`:  nil,
+		`"ab"`:      nil,
+		`2`:         nil,
+	}
+	for document, suggestion := range suggestions {
+		result, err := schema.ValidateWithOptions(NewStringLoader(document), ValidateOptions{SuggestEnum: true})
+		assert.Nil(t, err)
+		if assert.Len(t, result.Errors(), 1, document) {
+			assert.Equal(t, suggestion, result.Errors()[0].Details[STRING_SUGGESTION], document)
+		}
+	}
+
+	// only when asked for
+	result, err := schema.Validate(NewStringLoader(`"activ"`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Nil(t, result.Errors()[0].Details)
+	}
+}
+
+func TestMaxDecimalsExtension(t *testing.T) {
+
+	schema, err := NewSchemaWithOptions(NewStringLoader(`{"x-maxDecimals": 2}`), SchemaLoaderOptions{EnableExtensions: true})
+	assert.Nil(t, err)
+
+	for document, valid := range map[string]bool{`19.99`: true, `0.07`: true, `1.10`: true, `5`: true, `-3.5`: true, `1e2`: true, `1.005`: false, `0.001`: false, `1e-3`: false, `"1.005"`: true} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	// where multipleOf suffers from the imprecision of floats
+	assert.False(t, isMultipleOf(19.99, 0.01))
+
+	// the digits are counted as written, beyond the precision of floats
+	nested, err := NewSchemaWithOptions(NewStringLoader(`{"properties": {"a": {"items": {"x-maxDecimals": 2}}}}`), SchemaLoaderOptions{EnableExtensions: true})
+	assert.Nil(t, err)
+	for document, valid := range map[string]bool{
+		`0.1000000000000000001`:          false,
+		`12345678901234567.891`:          false,
+		`1.5e-3`:                         false,
+		`19.9900000000000000000`:         true,
+		`1.5E+1`:                         true,
+		`{"a": [1.5e-3]}`:                false,
+		`{"a": [1.5e-1, 1999e-2]}`:       true,
+		`{"a": [0.1000000000000000001]}`: false,
+	} {
+		s := schema
+		if strings.HasPrefix(document, "{") {
+			s = nested
+		}
+		result, err := s.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	// as written whichever way the document is validated
+	var out interface{}
+	result, err := schema.ValidateInto(NewStringLoader(`0.1000000000000000001`), &out)
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+	_, result, err = nested.ValidateNormalize(NewStringLoader(`{"a": [0.1000000000000000001]}`))
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+	result, err = ValidateAllOf([]*Schema{schema}, NewStringLoader(`0.1000000000000000001`))
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+
+	result, err = schema.Validate(NewStringLoader(`1.005`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, KEY_X_MAX_DECIMALS, result.Errors()[0].Reason)
+		assert.Equal(t, "#: x-maxDecimals,2", result.Errors()[0].String())
+	}
+
+	// ignored without the extensions
+	schema, err = NewSchema(NewStringLoader(`{"x-maxDecimals": 2}`))
+	assert.Nil(t, err)
+	result, err = schema.Validate(NewStringLoader(`1.005`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	_, err = NewSchemaWithOptions(NewStringLoader(`{"x-maxDecimals": -1}`), SchemaLoaderOptions{EnableExtensions: true})
+	assert.EqualError(t, err, `x-maxDecimals must be greater than or equal to 0`)
+	_, err = NewSchemaWithOptions(NewStringLoader(`{"x-maxDecimals": 1.5}`), SchemaLoaderOptions{EnableExtensions: true})
+	assert.EqualError(t, err, `x-maxDecimals must be of an integer`)
+}
+
+func TestValidateInto(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{"properties": {"id": {"type": "number"}, "name": {"type": "string"}}, "required": ["id"]}`))
+	assert.Nil(t, err)
+
+	type record struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}
+
+	var r record
+	result, err := schema.ValidateInto(NewStringLoader(`{"id": 9007199254740993, "name": "a"}`), &r)
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+	// decoded from the source, not from the float64 of the validation
+	assert.Equal(t, record{ID: 9007199254740993, Name: "a"}, r)
+
+	r = record{Name: "untouched"}
+	result, err = schema.ValidateInto(NewStringLoader(`{"name": "b"}`), &r)
+	assert.Nil(t, err)
+	assert.False(t, result.Valid())
+	assert.Equal(t, record{Name: "untouched"}, r)
+
+	r = record{}
+	result, err = schema.ValidateInto(NewGoLoader(map[string]interface{}{"id": 2}), &r)
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+	assert.Equal(t, record{ID: 2}, r)
+
+	// valid, but not for out
+	var s string
+	result, err = schema.ValidateInto(NewStringLoader(`{"id": 1}`), &s)
+	assert.NotNil(t, err)
+	assert.True(t, result.Valid())
+
+	_, err = schema.ValidateInto(NewStringLoader(`{`), &r)
+	assert.NotNil(t, err)
+}
+
+func TestValidateGoMaps(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"properties": {"1": {"type": "string"}, "red": {"type": "number"}},
+		"additionalProperties": false
+	}`))
+	assert.Nil(t, err)
+
+	result := schema.validateDocument(map[int]interface{}{1: "a"}, ValidateOptions{})
+	assert.True(t, result.Valid())
+	result = schema.validateDocument(map[int]interface{}{1: 2.0, 3: "b"}, ValidateOptions{})
+	assert.Len(t, result.Errors(), 2)
+
+	result = schema.validateDocument(map[testColor]interface{}{"red": 1.0}, ValidateOptions{})
+	assert.True(t, result.Valid())
+	result = schema.validateDocument(map[testColor]interface{}{"red": "1"}, ValidateOptions{})
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, "#/red", result.Errors()[0].Context.String())
+	}
+
+	result = schema.validateDocument(map[float64]interface{}{1.5: "a"}, ValidateOptions{})
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, KEY_TYPE, result.Errors()[0].Reason)
+		assert.Equal(t, "Map key 1.5 of type float64 cannot be a property name", result.Errors()[0].Requirement)
+	}
+}
+
+func TestDiscriminatorExtension(t *testing.T) {
+
+	source := `{
+		"definitions": {
+			"cat": {"properties": {"kind": {"enum": ["cat"]}, "lives": {"type": "integer"}}, "required": ["kind", "lives"]},
+			"dog": {"properties": {"kind": {"enum": ["dog"]}, "bark": {"type": "string"}}, "required": ["kind", "bark"]}
+		},
+		"oneOf": [{"$ref": "#/definitions/cat"}, {"$ref": "#/definitions/dog"}],
+		"x-discriminator": {"propertyName": "kind", "mapping": {"cat": "#/definitions/cat", "dog": "#/definitions/dog"}}
+	}`
+	schema, err := NewSchemaWithOptions(NewStringLoader(source), SchemaLoaderOptions{EnableExtensions: true})
+	assert.Nil(t, err)
+
+	for document, valid := range map[string]bool{
+		`{"kind": "cat", "lives": 9}`:   true,
+		`{"kind": "dog", "bark": "wo"}`: true,
+		`{"kind": "dog", "lives": 9}`:   false,
+		`{"kind": "cow"}`:               false,
+		`{"lives": 9}`:                  false,
+		`"cat"`:                         false,
+	} {
+		result, err := schema.Validate(NewStringLoader(document))
+		assert.Nil(t, err)
+		assert.Equal(t, valid, result.Valid(), document)
+	}
+
+	// only the mapped subSchema is validated : its errors are the ones reported
+	result, err := schema.Validate(NewStringLoader(`{"kind": "dog", "lives": 9}`))
+	assert.Nil(t, err)
+	if assert.Len(t, result.Errors(), 1) {
+		assert.Equal(t, "#/bark", result.Errors()[0].Context.String())
+		assert.Equal(t, KEY_REQUIRED, result.Errors()[0].Reason)
+	}
+
+	// skipped along with oneOf by the quick pass
+	result, err = schema.ValidateWithOptions(NewStringLoader(`{"kind": "dog", "lives": 9}`), ValidateOptions{StructuralOnly: true})
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	// round trips
+	marshaled, err := json.Marshal(marshalSubSchema(schema.rootSchema))
+	assert.Nil(t, err)
+	assert.Contains(t, string(marshaled), `"x-discriminator":{"mapping":{"cat":"#/definitions/cat","dog":"#/definitions/dog"},"propertyName":"kind"}`)
+	compiled, err := schema.MarshalCompiled()
+	assert.Nil(t, err)
+	schema, err = LoadCompiled(compiled)
+	assert.Nil(t, err)
+	result, err = schema.Validate(NewStringLoader(`{"kind": "dog", "bark": "wo"}`))
+	assert.Nil(t, err)
+	assert.True(t, result.Valid())
+
+	extensions := SchemaLoaderOptions{EnableExtensions: true}
+	_, err = NewSchemaWithOptions(NewStringLoader(`{"x-discriminator": {"propertyName": "kind"}}`), extensions)
+	assert.EqualError(t, err, `x-discriminator cannot be used without oneOf`)
+	_, err = NewSchemaWithOptions(NewStringLoader(`{"oneOf": [{}], "x-discriminator": {}}`), extensions)
+	assert.EqualError(t, err, `x-discriminator propertyName must be of type string`)
+	_, err = NewSchemaWithOptions(NewStringLoader(`{"oneOf": [{}], "x-discriminator": {"propertyName": "kind", "mapping": {"a": "#/definitions/a"}}, "definitions": {"a": {}}}`), extensions)
+	assert.EqualError(t, err, `Reference #/definitions/a is not the $ref of one of the oneOf subSchemas`)
+}
+
+func TestProfile(t *testing.T) {
+
+	schema, err := NewSchema(NewStringLoader(`{
+		"items": {"type": "string", "pattern": "^[a-z]+$"},
+		"uniqueItems": true
+	}`))
+	assert.Nil(t, err)
+
+	result, err := schema.ValidateWithOptions(NewStringLoader(`["a", "b", "c"]`), ValidateOptions{Profile: true})
+	assert.Nil(t, err)
+	profile := result.Profile()
+	for _, name := range []string{"validateArray", "validateString", "validateCommon", KEY_PATTERN, KEY_UNIQUE_ITEMS} {
+		_, ok := profile[name]
+		assert.True(t, ok, name)
+	}
+	_, ok := profile[KEY_ENUM]
+	assert.False(t, ok)
+	// the items are validated within validateArray
+	assert.True(t, profile["validateArray"] >= profile["validateString"])
+
+	// only when asked for
+	result, err = schema.Validate(NewStringLoader(`["a"]`))
+	assert.Nil(t, err)
+	assert.Nil(t, result.Profile())
+}